@@ -1,8 +1,9 @@
-package application
 // Package application contains auth service use cases and DTOs
 package application
 
 import (
+	"time"
+
 	"github.com/southern-martin/zride/backend/shared/application"
 )
 
@@ -74,6 +75,64 @@ func NewUpdateProfileCommand(userID, name, phone, email, avatar string) *UpdateP
 	}
 }
 
+// LockUserCommand represents administrative account lock command
+type LockUserCommand struct {
+	application.BaseCommand
+	UserID string `json:"user_id" binding:"required"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+func NewLockUserCommand(userID, reason string) *LockUserCommand {
+	return &LockUserCommand{
+		BaseCommand: application.NewBaseCommand("auth.lock_user"),
+		UserID:      userID,
+		Reason:      reason,
+	}
+}
+
+// UnlockUserCommand represents administrative account unlock command
+type UnlockUserCommand struct {
+	application.BaseCommand
+	UserID string `json:"user_id" binding:"required"`
+}
+
+func NewUnlockUserCommand(userID string) *UnlockUserCommand {
+	return &UnlockUserCommand{
+		BaseCommand: application.NewBaseCommand("auth.unlock_user"),
+		UserID:      userID,
+	}
+}
+
+// GrantRoleCommand represents role grant command
+type GrantRoleCommand struct {
+	application.BaseCommand
+	UserID string `json:"user_id" binding:"required"`
+	Role   string `json:"role" binding:"required"`
+}
+
+func NewGrantRoleCommand(userID, role string) *GrantRoleCommand {
+	return &GrantRoleCommand{
+		BaseCommand: application.NewBaseCommand("auth.grant_role"),
+		UserID:      userID,
+		Role:        role,
+	}
+}
+
+// RevokeRoleCommand represents role revoke command
+type RevokeRoleCommand struct {
+	application.BaseCommand
+	UserID string `json:"user_id" binding:"required"`
+	Role   string `json:"role" binding:"required"`
+}
+
+func NewRevokeRoleCommand(userID, role string) *RevokeRoleCommand {
+	return &RevokeRoleCommand{
+		BaseCommand: application.NewBaseCommand("auth.revoke_role"),
+		UserID:      userID,
+		Role:        role,
+	}
+}
+
 // GetUserQuery represents get user query
 type GetUserQuery struct {
 	application.BaseQuery
@@ -100,6 +159,32 @@ func NewValidateTokenQuery(accessToken string) *ValidateTokenQuery {
 	}
 }
 
+// IntrospectTokenQuery represents token introspection query from other services
+type IntrospectTokenQuery struct {
+	application.BaseQuery
+	Token string `json:"token" binding:"required"`
+}
+
+func NewIntrospectTokenQuery(token string) *IntrospectTokenQuery {
+	return &IntrospectTokenQuery{
+		BaseQuery: application.NewBaseQuery("auth.introspect_token"),
+		Token:     token,
+	}
+}
+
+// GetSessionSummaryQuery represents active session summary query
+type GetSessionSummaryQuery struct {
+	application.BaseQuery
+	UserID string `json:"user_id" binding:"required"`
+}
+
+func NewGetSessionSummaryQuery(userID string) *GetSessionSummaryQuery {
+	return &GetSessionSummaryQuery{
+		BaseQuery: application.NewBaseQuery("auth.get_session_summary"),
+		UserID:    userID,
+	}
+}
+
 // Response DTOs
 type LoginResponseDTO struct {
 	AccessToken  string  `json:"access_token"`
@@ -119,20 +204,42 @@ type RefreshTokenResponseDTO struct {
 
 type UserDTO struct {
 	application.BaseDTO
-	ZaloID      string `json:"zalo_id"`
-	Name        string `json:"name"`
-	Phone       string `json:"phone"`
-	Email       string `json:"email"`
-	Avatar      string `json:"avatar"`
-	IsActive    bool   `json:"is_active"`
-	LastLoginAt string `json:"last_login_at,omitempty"`
+	ZaloID      string   `json:"zalo_id"`
+	Name        string   `json:"name"`
+	Phone       string   `json:"phone"`
+	Email       string   `json:"email"`
+	Avatar      string   `json:"avatar"`
+	Roles       []string `json:"roles"`
+	IsActive    bool     `json:"is_active"`
+	IsLocked    bool     `json:"is_locked"`
+	LastLoginAt string   `json:"last_login_at,omitempty"`
 }
 
 type TokenValidationResponseDTO struct {
-	Valid  bool      `json:"valid"`
-	UserID string    `json:"user_id,omitempty"`
-	ZaloID string    `json:"zalo_id,omitempty"`
-	User   *UserDTO  `json:"user,omitempty"`
+	Valid  bool     `json:"valid"`
+	UserID string   `json:"user_id,omitempty"`
+	ZaloID string   `json:"zalo_id,omitempty"`
+	Roles  []string `json:"roles,omitempty"`
+	User   *UserDTO `json:"user,omitempty"`
+}
+
+type TokenIntrospectionResponseDTO struct {
+	Active    bool     `json:"active"`
+	UserID    string   `json:"user_id,omitempty"`
+	ZaloID    string   `json:"zalo_id,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+}
+
+type SessionSummaryResponseDTO struct {
+	ActiveSessions int                       `json:"active_sessions"`
+	Devices        []SessionDeviceSummaryDTO `json:"devices"`
+}
+
+type SessionDeviceSummaryDTO struct {
+	DeviceType   string    `json:"device_type"`
+	Sessions     int       `json:"sessions"`
+	LastActiveAt time.Time `json:"last_active_at"`
 }
 
 // Request DTOs
@@ -144,9 +251,17 @@ type RefreshTokenRequestDTO struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
+type LockUserRequestDTO struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type RoleRequestDTO struct {
+	Role string `json:"role" binding:"required"`
+}
+
 type UpdateProfileRequestDTO struct {
 	Name   string `json:"name" binding:"required"`
 	Phone  string `json:"phone"`
 	Email  string `json:"email"`
 	Avatar string `json:"avatar"`
-}
\ No newline at end of file
+}
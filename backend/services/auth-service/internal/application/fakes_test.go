@@ -0,0 +1,321 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/southern-martin/zride/backend/services/auth-service/internal/domain"
+	sharedDomain "github.com/southern-martin/zride/backend/shared/domain"
+)
+
+// inMemorySessionRepository is an AuthSessionRepository backed by a slice.
+// Like a lazy store, FindActiveByUserID returns every session of the user and leaves
+// filtering of revoked and expired sessions to the caller.
+type inMemorySessionRepository struct {
+	sessions []*domain.AuthSession
+}
+
+func (r *inMemorySessionRepository) Save(ctx context.Context, session *domain.AuthSession) error {
+	for i, existing := range r.sessions {
+		if existing.ID == session.ID {
+			r.sessions[i] = session
+			return nil
+		}
+	}
+	r.sessions = append(r.sessions, session)
+	return nil
+}
+
+func (r *inMemorySessionRepository) FindByAccessToken(ctx context.Context, token string) (*domain.AuthSession, error) {
+	for _, session := range r.sessions {
+		if session.AccessToken == token {
+			return session, nil
+		}
+	}
+	return nil, sharedDomain.ErrNotFound
+}
+
+func (r *inMemorySessionRepository) FindByRefreshToken(ctx context.Context, token string) (*domain.AuthSession, error) {
+	for _, session := range r.sessions {
+		if session.RefreshToken == token {
+			return session, nil
+		}
+	}
+	return nil, sharedDomain.ErrNotFound
+}
+
+func (r *inMemorySessionRepository) FindActiveByUserID(ctx context.Context, userID string) ([]*domain.AuthSession, error) {
+	var result []*domain.AuthSession
+	for _, session := range r.sessions {
+		if session.UserID == userID {
+			result = append(result, session)
+		}
+	}
+	return result, nil
+}
+
+func (r *inMemorySessionRepository) FindRecentByUserID(ctx context.Context, userID string, limit int) ([]*domain.AuthSession, error) {
+	result, _ := r.FindActiveByUserID(ctx, userID)
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (r *inMemorySessionRepository) RevokeSession(ctx context.Context, sessionID string) error {
+	for _, session := range r.sessions {
+		if session.ID.String() == sessionID {
+			session.Revoke()
+			return nil
+		}
+	}
+	return sharedDomain.ErrNotFound
+}
+
+func (r *inMemorySessionRepository) RevokeAllUserSessions(ctx context.Context, userID string) error {
+	for _, session := range r.sessions {
+		if session.UserID == userID {
+			session.Revoke()
+		}
+	}
+	return nil
+}
+
+func (r *inMemorySessionRepository) CleanupExpiredSessions(ctx context.Context) error {
+	kept := r.sessions[:0]
+	for _, session := range r.sessions {
+		if !session.IsExpired() {
+			kept = append(kept, session)
+		}
+	}
+	r.sessions = kept
+	return nil
+}
+
+// inMemoryUserRepository is a UserRepository backed by a map keyed by user ID.
+// Lookups miss with ErrNotFound plus details, like the Postgres repository.
+type inMemoryUserRepository struct {
+	users map[string]*domain.User
+}
+
+func newInMemoryUserRepository(users ...*domain.User) *inMemoryUserRepository {
+	repo := &inMemoryUserRepository{users: make(map[string]*domain.User)}
+	for _, user := range users {
+		repo.users[user.GetID()] = user
+	}
+	return repo
+}
+
+func (r *inMemoryUserRepository) Save(ctx context.Context, user *domain.User) error {
+	r.users[user.GetID()] = user
+	return nil
+}
+
+func (r *inMemoryUserRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+	return nil, sharedDomain.ErrNotFound.WithDetails("user_id", id)
+}
+
+func (r *inMemoryUserRepository) Delete(ctx context.Context, id string) error {
+	delete(r.users, id)
+	return nil
+}
+
+func (r *inMemoryUserRepository) Exists(ctx context.Context, id string) (bool, error) {
+	_, ok := r.users[id]
+	return ok, nil
+}
+
+func (r *inMemoryUserRepository) FindByZaloID(ctx context.Context, zaloID string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.ZaloID == zaloID {
+			return user, nil
+		}
+	}
+	return nil, sharedDomain.ErrNotFound.WithDetails("zalo_id", zaloID)
+}
+
+func (r *inMemoryUserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, sharedDomain.ErrNotFound.WithDetails("email", email)
+}
+
+func (r *inMemoryUserRepository) FindByPhone(ctx context.Context, phone string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.Phone == phone {
+			return user, nil
+		}
+	}
+	return nil, sharedDomain.ErrNotFound.WithDetails("phone", phone)
+}
+
+func (r *inMemoryUserRepository) UpdateLastLogin(ctx context.Context, userID string) error {
+	user, err := r.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	user.UpdateLastLogin()
+	return nil
+}
+
+func (r *inMemoryUserRepository) UpdateRefreshToken(ctx context.Context, userID, refreshToken string) error {
+	user, err := r.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	user.SetRefreshToken(refreshToken)
+	return nil
+}
+
+func (r *inMemoryUserRepository) FindActiveUsers(ctx context.Context, params *sharedDomain.PaginationParams) (*sharedDomain.PaginatedResult[*domain.User], error) {
+	var items []*domain.User
+	for _, user := range r.users {
+		if user.IsActive {
+			items = append(items, user)
+		}
+	}
+	return &sharedDomain.PaginatedResult[*domain.User]{Items: items, TotalItems: len(items), Page: 1, PageSize: len(items), TotalPages: 1}, nil
+}
+
+// recordingEventPublisher collects published events, or fails with err when set
+type recordingEventPublisher struct {
+	events []sharedDomain.DomainEvent
+	err    error
+}
+
+func (p *recordingEventPublisher) Publish(ctx context.Context, event sharedDomain.DomainEvent) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.events = append(p.events, event)
+	return nil
+}
+
+// eventTypes lists the types of published events in order
+func (p *recordingEventPublisher) eventTypes() []string {
+	types := make([]string, 0, len(p.events))
+	for _, event := range p.events {
+		types = append(types, event.GetEventType())
+	}
+	return types
+}
+
+// failingHistorySessionRepository fails FindRecentByUserID to simulate a history lookup outage
+type failingHistorySessionRepository struct {
+	*inMemorySessionRepository
+}
+
+func (r *failingHistorySessionRepository) FindRecentByUserID(ctx context.Context, userID string, limit int) ([]*domain.AuthSession, error) {
+	return nil, fmt.Errorf("pq: canceling statement due to statement timeout")
+}
+
+// rateLimitCall records one RateLimiter.Allow call
+type rateLimitCall struct {
+	key   string
+	limit domain.RateLimit
+}
+
+// fakeRateLimiter records calls and denies keys listed in deny
+type fakeRateLimiter struct {
+	calls []rateLimitCall
+	deny  map[string]bool
+	err   error
+	// onAllow, if set, runs on every call, e.g. to check call ordering
+	onAllow func(key string)
+}
+
+func (l *fakeRateLimiter) Allow(ctx context.Context, key string, limit domain.RateLimit) (bool, error) {
+	l.calls = append(l.calls, rateLimitCall{key: key, limit: limit})
+	if l.onAllow != nil {
+		l.onAllow(key)
+	}
+	if l.err != nil {
+		return false, l.err
+	}
+	return !l.deny[key], nil
+}
+
+// fakeZaloService resolves access tokens from a fixed map
+type fakeZaloService struct {
+	users map[string]*domain.ZaloUserInfo
+	calls int
+}
+
+func (s *fakeZaloService) VerifyAccessToken(ctx context.Context, accessToken string) (*domain.ZaloUserInfo, error) {
+	s.calls++
+	if user, ok := s.users[accessToken]; ok {
+		return user, nil
+	}
+	return nil, sharedDomain.ErrUnauthorized
+}
+
+func (s *fakeZaloService) GetUserProfile(ctx context.Context, accessToken string) (*domain.ZaloUserInfo, error) {
+	return s.VerifyAccessToken(ctx, accessToken)
+}
+
+func (s *fakeZaloService) RefreshAccessToken(ctx context.Context, refreshToken string) (*domain.ZaloTokenResponse, error) {
+	return nil, sharedDomain.ErrUnauthorized
+}
+
+// fakeTokenService issues opaque numbered tokens and remembers their claims
+type fakeTokenService struct {
+	issued int
+	claims map[string]*domain.TokenClaims
+}
+
+func newFakeTokenService() *fakeTokenService {
+	return &fakeTokenService{claims: make(map[string]*domain.TokenClaims)}
+}
+
+func (s *fakeTokenService) issue(tokenType, userID string, roles []string) string {
+	s.issued++
+	token := fmt.Sprintf("%s-%s-%d", tokenType, userID, s.issued)
+	s.claims[token] = &domain.TokenClaims{
+		UserID:    userID,
+		TokenType: tokenType,
+		Roles:     roles,
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		IssuedAt:  time.Now().Unix(),
+	}
+	return token
+}
+
+func (s *fakeTokenService) GenerateAccessToken(userID string, roles []string) (string, error) {
+	return s.issue("access", userID, roles), nil
+}
+
+func (s *fakeTokenService) GenerateRefreshToken(userID string) (string, error) {
+	return s.issue("refresh", userID, nil), nil
+}
+
+func (s *fakeTokenService) validate(token, tokenType string) (*domain.TokenClaims, error) {
+	claims, ok := s.claims[token]
+	if !ok || claims.TokenType != tokenType {
+		return nil, sharedDomain.ErrUnauthorized
+	}
+	return claims, nil
+}
+
+func (s *fakeTokenService) ValidateAccessToken(token string) (*domain.TokenClaims, error) {
+	return s.validate(token, "access")
+}
+
+func (s *fakeTokenService) ValidateRefreshToken(token string) (*domain.TokenClaims, error) {
+	return s.validate(token, "refresh")
+}
+
+func (s *fakeTokenService) RevokeToken(token string) error {
+	delete(s.claims, token)
+	return nil
+}
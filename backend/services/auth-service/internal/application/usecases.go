@@ -3,6 +3,9 @@ package application
 
 import (
 	"context"
+	"errors"
+	"log"
+	"sort"
 	"time"
 
 	"github.com/southern-martin/zride/backend/services/auth-service/internal/domain"
@@ -10,37 +13,81 @@ import (
 	sharedDomain "github.com/southern-martin/zride/backend/shared/domain"
 )
 
+// LoginRateLimits holds login throttling limits
+type LoginRateLimits struct {
+	PerIP     domain.RateLimit
+	PerZaloID domain.RateLimit
+}
+
+// DefaultLoginRateLimits returns default login throttling limits
+func DefaultLoginRateLimits() LoginRateLimits {
+	return LoginRateLimits{
+		PerIP:     domain.RateLimit{Rate: 20, Burst: 30, Period: time.Minute},
+		PerZaloID: domain.RateLimit{Rate: 5, Burst: 10, Period: time.Minute},
+	}
+}
+
 // LoginUseCase handles user login
 type LoginUseCase struct {
-	userRepo        domain.UserRepository
-	sessionRepo     domain.AuthSessionRepository
-	zaloService     domain.ZaloService
-	tokenService    domain.TokenService
+	userRepo       domain.UserRepository
+	sessionRepo    domain.AuthSessionRepository
+	zaloService    domain.ZaloService
+	tokenService   domain.TokenService
+	rateLimiter    domain.RateLimiter
+	rateLimits     LoginRateLimits
+	eventPublisher domain.EventPublisher
 }
 
+// recentSessionsLimit is how many past sessions a new login is compared against
+const recentSessionsLimit = 20
+
+// unknownIPAddress is the rate limit key for logins without a client IP, so they share one bucket
+// instead of bypassing the per-IP limit
+const unknownIPAddress = "unknown"
+
 // NewLoginUseCase creates new login use case
 func NewLoginUseCase(
 	userRepo domain.UserRepository,
 	sessionRepo domain.AuthSessionRepository,
 	zaloService domain.ZaloService,
 	tokenService domain.TokenService,
+	rateLimiter domain.RateLimiter,
+	rateLimits LoginRateLimits,
+	eventPublisher domain.EventPublisher,
 ) *LoginUseCase {
 	return &LoginUseCase{
-		userRepo:     userRepo,
-		sessionRepo:  sessionRepo,
-		zaloService:  zaloService,
-		tokenService: tokenService,
+		userRepo:       userRepo,
+		sessionRepo:    sessionRepo,
+		zaloService:    zaloService,
+		tokenService:   tokenService,
+		rateLimiter:    rateLimiter,
+		rateLimits:     rateLimits,
+		eventPublisher: eventPublisher,
 	}
 }
 
 // Execute executes login use case
 func (uc *LoginUseCase) Execute(ctx context.Context, cmd *LoginCommand) (*LoginResponseDTO, error) {
+	// Throttle by IP before hitting Zalo
+	ipAddress := cmd.IPAddress
+	if ipAddress == "" {
+		ipAddress = unknownIPAddress
+	}
+	if err := uc.checkRateLimit(ctx, "login:ip:"+ipAddress, uc.rateLimits.PerIP); err != nil {
+		return nil, err
+	}
+
 	// Verify Zalo access token
 	zaloUser, err := uc.zaloService.VerifyAccessToken(ctx, cmd.ZaloAccessToken)
 	if err != nil {
 		return nil, err
 	}
 
+	// Throttle by resolved Zalo account
+	if err := uc.checkRateLimit(ctx, "login:zalo:"+zaloUser.ID, uc.rateLimits.PerZaloID); err != nil {
+		return nil, err
+	}
+
 	// Check if user exists
 	user, err := uc.userRepo.FindByZaloID(ctx, zaloUser.ID)
 	if err != nil {
@@ -50,11 +97,32 @@ func (uc *LoginUseCase) Execute(ctx context.Context, cmd *LoginCommand) (*LoginR
 			return nil, err
 		}
 
+		// Zalo may report a phone or email that already belongs to another account.
+		// Drop it rather than block the login; the user can set their own later.
+		phoneTaken, emailTaken, err := findContactConflicts(ctx, uc.userRepo, user.GetID(), user.Phone, user.Email)
+		if err != nil {
+			return nil, err
+		}
+		if phoneTaken || emailTaken {
+			log.Printf("WARN zalo contact already in use, not copied to new account: zalo_id=%s phone_taken=%t email_taken=%t", zaloUser.ID, phoneTaken, emailTaken)
+		}
+		if phoneTaken {
+			user.Phone = ""
+		}
+		if emailTaken {
+			user.Email = ""
+		}
+
 		if err := uc.userRepo.Save(ctx, user); err != nil {
 			return nil, err
 		}
 	}
 
+	// Locked accounts get a specific error rather than a generic not-found
+	if user.IsLocked {
+		return nil, domain.ErrAccountLocked
+	}
+
 	// Update last login
 	user.UpdateLastLogin()
 	if err := uc.userRepo.Save(ctx, user); err != nil {
@@ -62,7 +130,7 @@ func (uc *LoginUseCase) Execute(ctx context.Context, cmd *LoginCommand) (*LoginR
 	}
 
 	// Generate tokens
-	accessToken, err := uc.tokenService.GenerateAccessToken(user.GetID())
+	accessToken, err := uc.tokenService.GenerateAccessToken(user.GetID(), user.Roles)
 	if err != nil {
 		return nil, err
 	}
@@ -83,6 +151,15 @@ func (uc *LoginUseCase) Execute(ctx context.Context, cmd *LoginCommand) (*LoginR
 		expiresAt,
 	)
 
+	// Flag logins from a device or IP not seen in recent sessions. Detection is best-effort:
+	// if history can't be loaded the login proceeds unflagged.
+	recentSessions, err := uc.sessionRepo.FindRecentByUserID(ctx, user.GetID(), recentSessionsLimit)
+	if err != nil {
+		log.Printf("WARN suspicious login check skipped: user_id=%s err=%v", user.GetID(), err)
+	} else if len(recentSessions) > 0 && !session.IsFromKnownSource(recentSessions) {
+		session.MarkSuspicious()
+	}
+
 	if err := uc.sessionRepo.Save(ctx, session); err != nil {
 		return nil, err
 	}
@@ -93,6 +170,20 @@ func (uc *LoginUseCase) Execute(ctx context.Context, cmd *LoginCommand) (*LoginR
 		return nil, err
 	}
 
+	if session.IsSuspicious {
+		event := &domain.SuspiciousLogin{
+			BaseDomainEvent: sharedDomain.NewDomainEvent(domain.SuspiciousLoginEvent, user.ID, nil),
+			UserID:          user.GetID(),
+			SessionID:       session.ID.String(),
+			DeviceInfo:      session.DeviceInfo,
+			IPAddress:       session.IPAddress,
+		}
+		// Notification is best-effort and must not fail an otherwise valid login
+		if err := uc.eventPublisher.Publish(ctx, event); err != nil {
+			log.Printf("WARN suspicious login event not published: user_id=%s session_id=%s err=%v", user.GetID(), session.ID, err)
+		}
+	}
+
 	return &LoginResponseDTO{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
@@ -102,6 +193,18 @@ func (uc *LoginUseCase) Execute(ctx context.Context, cmd *LoginCommand) (*LoginR
 	}, nil
 }
 
+// checkRateLimit consumes a login attempt for key, returning ErrTooManyRequests when exhausted
+func (uc *LoginUseCase) checkRateLimit(ctx context.Context, key string, limit domain.RateLimit) error {
+	allowed, err := uc.rateLimiter.Allow(ctx, key, limit)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return sharedDomain.ErrTooManyRequests
+	}
+	return nil
+}
+
 // RefreshTokenUseCase handles token refresh
 type RefreshTokenUseCase struct {
 	userRepo     domain.UserRepository
@@ -147,8 +250,12 @@ func (uc *RefreshTokenUseCase) Execute(ctx context.Context, cmd *RefreshTokenCom
 		return nil, err
 	}
 
+	if user.IsLocked {
+		return nil, domain.ErrAccountLocked
+	}
+
 	// Generate new tokens
-	accessToken, err := uc.tokenService.GenerateAccessToken(user.GetID())
+	accessToken, err := uc.tokenService.GenerateAccessToken(user.GetID(), user.Roles)
 	if err != nil {
 		return nil, err
 	}
@@ -223,6 +330,58 @@ func (uc *LogoutUseCase) Execute(ctx context.Context, cmd *LogoutCommand) error
 	return uc.tokenService.RevokeToken(cmd.AccessToken)
 }
 
+// UpdateProfileUseCase handles user profile updates
+type UpdateProfileUseCase struct {
+	userRepo       domain.UserRepository
+	eventPublisher domain.EventPublisher
+}
+
+// NewUpdateProfileUseCase creates new update profile use case
+func NewUpdateProfileUseCase(
+	userRepo domain.UserRepository,
+	eventPublisher domain.EventPublisher,
+) *UpdateProfileUseCase {
+	return &UpdateProfileUseCase{
+		userRepo:       userRepo,
+		eventPublisher: eventPublisher,
+	}
+}
+
+// Execute executes update profile use case
+func (uc *UpdateProfileUseCase) Execute(ctx context.Context, cmd *UpdateProfileCommand) (*UserDTO, error) {
+	user, err := uc.userRepo.FindByID(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Phone and email must not belong to another account
+	if err := ensureContactAvailable(ctx, uc.userRepo, user.GetID(), cmd.Phone, cmd.Email); err != nil {
+		return nil, err
+	}
+
+	if err := user.UpdateProfile(cmd.Name, cmd.Phone, cmd.Email, cmd.Avatar); err != nil {
+		return nil, sharedDomain.NewDomainError(sharedDomain.ErrValidation.Code, err.Error())
+	}
+
+	if err := uc.userRepo.Save(ctx, user); err != nil {
+		return nil, err
+	}
+
+	event := &domain.UserProfileUpdated{
+		BaseDomainEvent: sharedDomain.NewDomainEvent(domain.UserProfileUpdatedEvent, user.ID, nil),
+		UserID:          user.GetID(),
+		Name:            user.Name,
+		Email:           user.Email,
+		Phone:           user.Phone,
+	}
+	if err := uc.eventPublisher.Publish(ctx, event); err != nil {
+		return nil, err
+	}
+
+	userDTO := mapUserToDTO(user)
+	return &userDTO, nil
+}
+
 // GetUserUseCase handles get user profile
 type GetUserUseCase struct {
 	userRepo domain.UserRepository
@@ -285,19 +444,295 @@ func (uc *ValidateTokenUseCase) Execute(ctx context.Context, query *ValidateToke
 
 	// Find user
 	user, err := uc.userRepo.FindByID(ctx, claims.UserID)
-	if err != nil {
+	if err != nil || user.IsLocked {
 		return &TokenValidationResponseDTO{Valid: false}, nil
 	}
 
+	// Roles come from the user record rather than the token so revoked roles apply immediately
 	userDTO := mapUserToDTO(user)
 	return &TokenValidationResponseDTO{
 		Valid:  true,
 		UserID: user.GetID(),
 		ZaloID: user.ZaloID,
+		Roles:  user.Roles,
 		User:   &userDTO,
 	}, nil
 }
 
+// IntrospectTokenUseCase handles token introspection for other services
+type IntrospectTokenUseCase struct {
+	userRepo     domain.UserRepository
+	sessionRepo  domain.AuthSessionRepository
+	tokenService domain.TokenService
+}
+
+// NewIntrospectTokenUseCase creates new introspect token use case
+func NewIntrospectTokenUseCase(
+	userRepo domain.UserRepository,
+	sessionRepo domain.AuthSessionRepository,
+	tokenService domain.TokenService,
+) *IntrospectTokenUseCase {
+	return &IntrospectTokenUseCase{
+		userRepo:     userRepo,
+		sessionRepo:  sessionRepo,
+		tokenService: tokenService,
+	}
+}
+
+// Execute executes introspect token use case
+func (uc *IntrospectTokenUseCase) Execute(ctx context.Context, query *IntrospectTokenQuery) (*TokenIntrospectionResponseDTO, error) {
+	inactive := &TokenIntrospectionResponseDTO{Active: false}
+
+	claims, err := uc.tokenService.ValidateAccessToken(query.Token)
+	if err != nil {
+		return inactive, nil
+	}
+
+	session, err := uc.sessionRepo.FindByAccessToken(ctx, query.Token)
+	if err != nil || !session.IsActive || session.IsExpired() {
+		return inactive, nil
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, claims.UserID)
+	if err != nil || user.IsLocked {
+		return inactive, nil
+	}
+
+	// Report whichever of token and session expires first
+	expiresAt := claims.ExpiresAt
+	if sessionExpiry := session.ExpiresAt.Unix(); expiresAt == 0 || sessionExpiry < expiresAt {
+		expiresAt = sessionExpiry
+	}
+
+	// Roles come from the user record rather than the token so revoked roles apply immediately
+	return &TokenIntrospectionResponseDTO{
+		Active:    true,
+		UserID:    user.GetID(),
+		ZaloID:    user.ZaloID,
+		Roles:     user.Roles,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// GetSessionSummaryUseCase handles active session summary for the security screen
+type GetSessionSummaryUseCase struct {
+	sessionRepo domain.AuthSessionRepository
+}
+
+// NewGetSessionSummaryUseCase creates new get session summary use case
+func NewGetSessionSummaryUseCase(sessionRepo domain.AuthSessionRepository) *GetSessionSummaryUseCase {
+	return &GetSessionSummaryUseCase{sessionRepo: sessionRepo}
+}
+
+// Execute executes get session summary use case
+func (uc *GetSessionSummaryUseCase) Execute(ctx context.Context, query *GetSessionSummaryQuery) (*SessionSummaryResponseDTO, error) {
+	sessions, err := uc.sessionRepo.FindActiveByUserID(ctx, query.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &SessionSummaryResponseDTO{Devices: []SessionDeviceSummaryDTO{}}
+	byDevice := make(map[string]*SessionDeviceSummaryDTO)
+	for _, session := range sessions {
+		if !session.IsActive || session.IsExpired() {
+			continue
+		}
+		summary.ActiveSessions++
+
+		deviceType := session.DeviceType()
+		device, ok := byDevice[deviceType]
+		if !ok {
+			device = &SessionDeviceSummaryDTO{DeviceType: deviceType}
+			byDevice[deviceType] = device
+		}
+		device.Sessions++
+		if session.UpdatedAt.After(device.LastActiveAt) {
+			device.LastActiveAt = session.UpdatedAt
+		}
+	}
+
+	for _, device := range byDevice {
+		summary.Devices = append(summary.Devices, *device)
+	}
+
+	// Most recently active device first
+	sort.Slice(summary.Devices, func(i, j int) bool {
+		return summary.Devices[i].LastActiveAt.After(summary.Devices[j].LastActiveAt)
+	})
+
+	return summary, nil
+}
+
+// LockUserUseCase handles administrative account locking
+type LockUserUseCase struct {
+	userRepo       domain.UserRepository
+	sessionRepo    domain.AuthSessionRepository
+	eventPublisher domain.EventPublisher
+}
+
+// NewLockUserUseCase creates new lock user use case
+func NewLockUserUseCase(
+	userRepo domain.UserRepository,
+	sessionRepo domain.AuthSessionRepository,
+	eventPublisher domain.EventPublisher,
+) *LockUserUseCase {
+	return &LockUserUseCase{
+		userRepo:       userRepo,
+		sessionRepo:    sessionRepo,
+		eventPublisher: eventPublisher,
+	}
+}
+
+// Execute executes lock user use case
+func (uc *LockUserUseCase) Execute(ctx context.Context, cmd *LockUserCommand) (*UserDTO, error) {
+	user, err := uc.userRepo.FindByID(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Lock(cmd.Reason)
+	if err := uc.userRepo.Save(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// Sign the user out everywhere
+	if err := uc.sessionRepo.RevokeAllUserSessions(ctx, user.GetID()); err != nil {
+		return nil, err
+	}
+
+	event := &domain.UserLocked{
+		BaseDomainEvent: sharedDomain.NewDomainEvent(domain.UserLockedEvent, user.ID, nil),
+		UserID:          user.GetID(),
+		Reason:          cmd.Reason,
+	}
+	if err := uc.eventPublisher.Publish(ctx, event); err != nil {
+		return nil, err
+	}
+
+	userDTO := mapUserToDTO(user)
+	return &userDTO, nil
+}
+
+// UnlockUserUseCase handles administrative account unlocking
+type UnlockUserUseCase struct {
+	userRepo domain.UserRepository
+}
+
+// NewUnlockUserUseCase creates new unlock user use case
+func NewUnlockUserUseCase(userRepo domain.UserRepository) *UnlockUserUseCase {
+	return &UnlockUserUseCase{userRepo: userRepo}
+}
+
+// Execute executes unlock user use case
+func (uc *UnlockUserUseCase) Execute(ctx context.Context, cmd *UnlockUserCommand) (*UserDTO, error) {
+	user, err := uc.userRepo.FindByID(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Unlock()
+	if err := uc.userRepo.Save(ctx, user); err != nil {
+		return nil, err
+	}
+
+	userDTO := mapUserToDTO(user)
+	return &userDTO, nil
+}
+
+// GrantRoleUseCase handles granting a role, e.g. driver after driver registration
+type GrantRoleUseCase struct {
+	userRepo domain.UserRepository
+}
+
+// NewGrantRoleUseCase creates new grant role use case
+func NewGrantRoleUseCase(userRepo domain.UserRepository) *GrantRoleUseCase {
+	return &GrantRoleUseCase{userRepo: userRepo}
+}
+
+// Execute executes grant role use case; granting a role the user already has is a no-op
+func (uc *GrantRoleUseCase) Execute(ctx context.Context, cmd *GrantRoleCommand) (*UserDTO, error) {
+	user, err := uc.userRepo.FindByID(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := user.GrantRole(cmd.Role); err != nil {
+		return nil, sharedDomain.NewDomainError(sharedDomain.ErrValidation.Code, err.Error())
+	}
+
+	if err := uc.userRepo.Save(ctx, user); err != nil {
+		return nil, err
+	}
+
+	userDTO := mapUserToDTO(user)
+	return &userDTO, nil
+}
+
+// RevokeRoleUseCase handles revoking a role
+type RevokeRoleUseCase struct {
+	userRepo domain.UserRepository
+}
+
+// NewRevokeRoleUseCase creates new revoke role use case
+func NewRevokeRoleUseCase(userRepo domain.UserRepository) *RevokeRoleUseCase {
+	return &RevokeRoleUseCase{userRepo: userRepo}
+}
+
+// Execute executes revoke role use case. Roles are read from the user record on every
+// validation and introspection, so the revocation applies without waiting for token expiry.
+func (uc *RevokeRoleUseCase) Execute(ctx context.Context, cmd *RevokeRoleCommand) (*UserDTO, error) {
+	user, err := uc.userRepo.FindByID(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.RevokeRole(cmd.Role)
+	if err := uc.userRepo.Save(ctx, user); err != nil {
+		return nil, err
+	}
+
+	userDTO := mapUserToDTO(user)
+	return &userDTO, nil
+}
+
+// ensureContactAvailable returns a CONFLICT error if phone or email is used by an account other than userID.
+// The error is built per call and does not echo the probed value back.
+func ensureContactAvailable(ctx context.Context, userRepo domain.UserRepository, userID, phone, email string) error {
+	phoneTaken, emailTaken, err := findContactConflicts(ctx, userRepo, userID, phone, email)
+	if err != nil {
+		return err
+	}
+	if phoneTaken {
+		return sharedDomain.NewDomainError(sharedDomain.ErrConflict.Code, "Phone already in use")
+	}
+	if emailTaken {
+		return sharedDomain.NewDomainError(sharedDomain.ErrConflict.Code, "Email already in use")
+	}
+	return nil
+}
+
+// findContactConflicts reports whether phone or email is used by an account other than userID.
+// Phones are compared in normalized form.
+func findContactConflicts(ctx context.Context, userRepo domain.UserRepository, userID, phone, email string) (phoneTaken, emailTaken bool, err error) {
+	if phone != "" {
+		existing, err := userRepo.FindByPhone(ctx, domain.NormalizePhone(phone))
+		if err != nil && !errors.Is(err, sharedDomain.ErrNotFound) {
+			return false, false, err
+		}
+		phoneTaken = err == nil && existing.GetID() != userID
+	}
+
+	if email != "" {
+		existing, err := userRepo.FindByEmail(ctx, email)
+		if err != nil && !errors.Is(err, sharedDomain.ErrNotFound) {
+			return false, false, err
+		}
+		emailTaken = err == nil && existing.GetID() != userID
+	}
+
+	return phoneTaken, emailTaken, nil
+}
+
 // Helper function to map domain user to DTO
 func mapUserToDTO(user *domain.User) UserDTO {
 	dto := UserDTO{
@@ -311,7 +746,9 @@ func mapUserToDTO(user *domain.User) UserDTO {
 		Phone:    user.Phone,
 		Email:    user.Email,
 		Avatar:   user.Avatar,
+		Roles:    user.Roles,
 		IsActive: user.IsActive,
+		IsLocked: user.IsLocked,
 	}
 
 	if user.LastLoginAt != nil {
@@ -0,0 +1,624 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/southern-martin/zride/backend/services/auth-service/internal/domain"
+	sharedDomain "github.com/southern-martin/zride/backend/shared/domain"
+)
+
+func newTestSession(userID, deviceInfo string, lastActive time.Time) *domain.AuthSession {
+	session := domain.NewAuthSession(userID, "access-"+deviceInfo+lastActive.String(), "refresh", deviceInfo, "10.0.0.1", time.Now().Add(time.Hour))
+	session.UpdatedAt = lastActive
+	return session
+}
+
+func TestGetSessionSummaryUseCase(t *testing.T) {
+	now := time.Now()
+	const (
+		iphone  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)"
+		android = "Mozilla/5.0 (Linux; Android 13; SM-A536E)"
+		browser = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/118.0"
+	)
+
+	revoked := newTestSession("user-1", browser, now)
+	revoked.Revoke()
+	expired := newTestSession("user-1", browser, now)
+	expired.ExpiresAt = now.Add(-time.Minute)
+
+	repo := &inMemorySessionRepository{sessions: []*domain.AuthSession{
+		newTestSession("user-1", iphone, now.Add(-3*time.Hour)),
+		newTestSession("user-1", android, now.Add(-10*time.Minute)),
+		newTestSession("user-1", iphone, now.Add(-time.Hour)),
+		newTestSession("user-1", "curl/8.4.0", now.Add(-2*time.Hour)),
+		newTestSession("user-2", browser, now),
+		revoked,
+		expired,
+	}}
+
+	summary, err := NewGetSessionSummaryUseCase(repo).Execute(context.Background(), NewGetSessionSummaryQuery("user-1"))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if summary.ActiveSessions != 4 {
+		t.Errorf("ActiveSessions = %d, want 4", summary.ActiveSessions)
+	}
+
+	want := []SessionDeviceSummaryDTO{
+		{DeviceType: domain.DeviceTypeAndroid, Sessions: 1, LastActiveAt: now.Add(-10 * time.Minute)},
+		{DeviceType: domain.DeviceTypeIOS, Sessions: 2, LastActiveAt: now.Add(-time.Hour)},
+		{DeviceType: domain.DeviceTypeOther, Sessions: 1, LastActiveAt: now.Add(-2 * time.Hour)},
+	}
+	if len(summary.Devices) != len(want) {
+		t.Fatalf("Devices = %+v, want %+v", summary.Devices, want)
+	}
+	for i, device := range summary.Devices {
+		if device.DeviceType != want[i].DeviceType || device.Sessions != want[i].Sessions || !device.LastActiveAt.Equal(want[i].LastActiveAt) {
+			t.Errorf("Devices[%d] = %+v, want %+v", i, device, want[i])
+		}
+	}
+}
+
+func TestGetSessionSummaryUseCaseNoSessions(t *testing.T) {
+	summary, err := NewGetSessionSummaryUseCase(&inMemorySessionRepository{}).Execute(context.Background(), NewGetSessionSummaryQuery("user-1"))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if summary.ActiveSessions != 0 || summary.Devices == nil || len(summary.Devices) != 0 {
+		t.Errorf("summary = %+v, want no sessions and an empty device list", summary)
+	}
+}
+
+func newTestUser(t *testing.T, zaloID, phone, email string) *domain.User {
+	t.Helper()
+	user, err := domain.NewUser(zaloID, "Test User", phone, email, "")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	return user
+}
+
+func TestEnsureContactAvailable(t *testing.T) {
+	owner := newTestUser(t, "zalo-owner", "0901234567", "owner@example.com")
+	other := newTestUser(t, "zalo-other", "0907654321", "other@example.com")
+	repo := newInMemoryUserRepository(owner, other)
+
+	tests := []struct {
+		name         string
+		userID       string
+		phone        string
+		email        string
+		wantConflict bool
+	}{
+		{"phone owned by another user", other.GetID(), owner.Phone, "", true},
+		{"phone owned by another user, +84 form", other.GetID(), "+84901234567", "", true},
+		{"phone owned by another user, 84 form", other.GetID(), "84901234567", "", true},
+		{"email owned by another user", other.GetID(), "", owner.Email, true},
+		{"own phone and email", owner.GetID(), owner.Phone, owner.Email, false},
+		{"unused phone and email", owner.GetID(), "0911111111", "new@example.com", false},
+		{"new user with unused contact", "not-yet-saved", "0911111111", "new@example.com", false},
+		{"no contact", other.GetID(), "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ensureContactAvailable(context.Background(), repo, tt.userID, tt.phone, tt.email)
+			if !tt.wantConflict {
+				if err != nil {
+					t.Fatalf("ensureContactAvailable() error = %v, want nil", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, sharedDomain.ErrConflict) {
+				t.Fatalf("ensureContactAvailable() error = %v, want CONFLICT", err)
+			}
+			var domainErr *sharedDomain.DomainError
+			if errors.As(err, &domainErr) && len(domainErr.Details) != 0 {
+				t.Errorf("conflict details = %v, want none", domainErr.Details)
+			}
+		})
+	}
+
+	if len(sharedDomain.ErrConflict.Details) != 0 || len(sharedDomain.ErrNotFound.Details) != 0 {
+		t.Errorf("shared errors were modified: conflict=%v not_found=%v", sharedDomain.ErrConflict.Details, sharedDomain.ErrNotFound.Details)
+	}
+}
+
+func TestUpdateProfileUseCaseRejectsPhoneOfAnotherUser(t *testing.T) {
+	owner := newTestUser(t, "zalo-owner", "0901234567", "owner@example.com")
+	other := newTestUser(t, "zalo-other", "0907654321", "other@example.com")
+	publisher := &recordingEventPublisher{}
+	uc := NewUpdateProfileUseCase(newInMemoryUserRepository(owner, other), publisher)
+
+	_, err := uc.Execute(context.Background(), NewUpdateProfileCommand(other.GetID(), "Other", owner.Phone, other.Email, ""))
+	if !errors.Is(err, sharedDomain.ErrConflict) {
+		t.Fatalf("Execute() error = %v, want CONFLICT", err)
+	}
+	if other.Phone != "0907654321" || len(publisher.events) != 0 {
+		t.Errorf("profile was updated despite conflict: phone=%s events=%d", other.Phone, len(publisher.events))
+	}
+
+	result, err := uc.Execute(context.Background(), NewUpdateProfileCommand(other.GetID(), "Other", other.Phone, "renamed@example.com", ""))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Email != "renamed@example.com" || len(publisher.events) != 1 {
+		t.Errorf("Execute() = %+v with %d events, want updated email and one event", result, len(publisher.events))
+	}
+}
+
+func TestUpdateProfileUseCaseValidationError(t *testing.T) {
+	user := newTestUser(t, "zalo-1", "0901234567", "")
+	uc := NewUpdateProfileUseCase(newInMemoryUserRepository(user), &recordingEventPublisher{})
+
+	_, err := uc.Execute(context.Background(), NewUpdateProfileCommand(user.GetID(), "Test User", "", "not-an-email", ""))
+	if !errors.Is(err, sharedDomain.ErrValidation) {
+		t.Fatalf("Execute() error = %v, want VALIDATION_ERROR", err)
+	}
+	if len(sharedDomain.ErrValidation.Details) != 0 {
+		t.Errorf("shared ErrValidation was modified: %v", sharedDomain.ErrValidation.Details)
+	}
+}
+
+// loginFixture wires LoginUseCase to in-memory fakes
+type loginFixture struct {
+	userRepo    *inMemoryUserRepository
+	sessionRepo *inMemorySessionRepository
+	zalo        *fakeZaloService
+	tokens      *fakeTokenService
+	limiter     *fakeRateLimiter
+	publisher   *recordingEventPublisher
+	useCase     *LoginUseCase
+}
+
+const testZaloToken = "zalo-access-token"
+
+func newLoginFixture(users ...*domain.User) *loginFixture {
+	f := &loginFixture{
+		userRepo:    newInMemoryUserRepository(users...),
+		sessionRepo: &inMemorySessionRepository{},
+		zalo: &fakeZaloService{users: map[string]*domain.ZaloUserInfo{
+			testZaloToken: {ID: "zalo-1", Name: "Nguyen Van Lan", Phone: "0901234567", Email: "lan@example.com"},
+		}},
+		tokens:    newFakeTokenService(),
+		limiter:   &fakeRateLimiter{deny: make(map[string]bool)},
+		publisher: &recordingEventPublisher{},
+	}
+	f.useCase = NewLoginUseCase(f.userRepo, f.sessionRepo, f.zalo, f.tokens, f.limiter, DefaultLoginRateLimits(), f.publisher)
+	return f
+}
+
+func (f *loginFixture) login(ipAddress, deviceInfo string) (*LoginResponseDTO, error) {
+	return f.useCase.Execute(context.Background(), &LoginCommand{
+		ZaloAccessToken: testZaloToken,
+		DeviceInfo:      deviceInfo,
+		IPAddress:       ipAddress,
+	})
+}
+
+func TestLoginUseCaseRateLimits(t *testing.T) {
+	limits := DefaultLoginRateLimits()
+	limiterErr := errors.New("redis: connection refused")
+
+	tests := []struct {
+		name          string
+		ipAddress     string
+		deny          string
+		limiterErr    error
+		zaloToken     string
+		wantErr       error
+		wantCalls     []rateLimitCall
+		wantZaloCalls int
+	}{
+		{
+			name:      "per-IP then per-Zalo-ID bucket",
+			ipAddress: "203.0.113.7",
+			wantCalls: []rateLimitCall{
+				{key: "login:ip:203.0.113.7", limit: limits.PerIP},
+				{key: "login:zalo:zalo-1", limit: limits.PerZaloID},
+			},
+			wantZaloCalls: 1,
+		},
+		{
+			name: "no IP shares the unknown bucket",
+			wantCalls: []rateLimitCall{
+				{key: "login:ip:unknown", limit: limits.PerIP},
+				{key: "login:zalo:zalo-1", limit: limits.PerZaloID},
+			},
+			wantZaloCalls: 1,
+		},
+		{
+			name:      "IP bucket exhausted stops before Zalo",
+			ipAddress: "203.0.113.7",
+			deny:      "login:ip:203.0.113.7",
+			wantErr:   sharedDomain.ErrTooManyRequests,
+			wantCalls: []rateLimitCall{
+				{key: "login:ip:203.0.113.7", limit: limits.PerIP},
+			},
+		},
+		{
+			name:      "Zalo ID bucket exhausted",
+			ipAddress: "203.0.113.7",
+			deny:      "login:zalo:zalo-1",
+			wantErr:   sharedDomain.ErrTooManyRequests,
+			wantCalls: []rateLimitCall{
+				{key: "login:ip:203.0.113.7", limit: limits.PerIP},
+				{key: "login:zalo:zalo-1", limit: limits.PerZaloID},
+			},
+			wantZaloCalls: 1,
+		},
+		{
+			name:       "limiter error stops the login",
+			ipAddress:  "203.0.113.7",
+			limiterErr: limiterErr,
+			wantErr:    limiterErr,
+			wantCalls: []rateLimitCall{
+				{key: "login:ip:203.0.113.7", limit: limits.PerIP},
+			},
+		},
+		{
+			name:      "invalid Zalo token does not consume the Zalo ID bucket",
+			ipAddress: "203.0.113.7",
+			zaloToken: "forged",
+			wantErr:   sharedDomain.ErrUnauthorized,
+			wantCalls: []rateLimitCall{
+				{key: "login:ip:203.0.113.7", limit: limits.PerIP},
+			},
+			wantZaloCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newLoginFixture()
+			if tt.deny != "" {
+				f.limiter.deny[tt.deny] = true
+			}
+			f.limiter.err = tt.limiterErr
+			f.limiter.onAllow = func(key string) {
+				if key == "login:ip:"+tt.ipAddress || key == "login:ip:unknown" {
+					if f.zalo.calls != 0 {
+						t.Errorf("per-IP bucket checked after VerifyAccessToken")
+					}
+				}
+			}
+
+			cmd := &LoginCommand{ZaloAccessToken: testZaloToken, DeviceInfo: "Zalo Mini App", IPAddress: tt.ipAddress}
+			if tt.zaloToken != "" {
+				cmd.ZaloAccessToken = tt.zaloToken
+			}
+			result, err := f.useCase.Execute(context.Background(), cmd)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+				}
+				if len(f.userRepo.users) != 0 || len(f.sessionRepo.sessions) != 0 {
+					t.Errorf("rejected login created %d users and %d sessions", len(f.userRepo.users), len(f.sessionRepo.sessions))
+				}
+			} else if err != nil || result == nil {
+				t.Fatalf("Execute() = %v, %v, want success", result, err)
+			}
+
+			if f.zalo.calls != tt.wantZaloCalls {
+				t.Errorf("VerifyAccessToken calls = %d, want %d", f.zalo.calls, tt.wantZaloCalls)
+			}
+			if len(f.limiter.calls) != len(tt.wantCalls) {
+				t.Fatalf("limiter calls = %+v, want %+v", f.limiter.calls, tt.wantCalls)
+			}
+			for i, call := range f.limiter.calls {
+				if call != tt.wantCalls[i] {
+					t.Errorf("limiter call %d = %+v, want %+v", i, call, tt.wantCalls[i])
+				}
+			}
+		})
+	}
+}
+
+const (
+	testIPhone  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)"
+	testAndroid = "Mozilla/5.0 (Linux; Android 13; SM-A536E)"
+)
+
+func TestLoginUseCaseLockedUser(t *testing.T) {
+	user := newTestUser(t, "zalo-1", "0901234567", "")
+	user.Lock("chargeback fraud")
+	f := newLoginFixture(user)
+
+	_, err := f.login("203.0.113.7", testIPhone)
+	if !errors.Is(err, domain.ErrAccountLocked) {
+		t.Fatalf("Execute() error = %v, want ErrAccountLocked", err)
+	}
+	if len(f.sessionRepo.sessions) != 0 || f.tokens.issued != 0 {
+		t.Errorf("locked login created %d sessions and %d tokens", len(f.sessionRepo.sessions), f.tokens.issued)
+	}
+}
+
+func TestLoginUseCaseNewUserContactConflicts(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  *domain.User
+		wantPhone string
+		wantEmail string
+	}{
+		{"no conflict", nil, "0901234567", "lan@example.com"},
+		{"phone taken", newTestUser(t, "zalo-other", "+84901234567", "other@example.com"), "", "lan@example.com"},
+		{"email taken", newTestUser(t, "zalo-other", "0907654321", "lan@example.com"), "0901234567", ""},
+		{"both taken", newTestUser(t, "zalo-other", "0901234567", "lan@example.com"), "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f *loginFixture
+			if tt.existing != nil {
+				f = newLoginFixture(tt.existing)
+			} else {
+				f = newLoginFixture()
+			}
+
+			if _, err := f.login("10.0.0.1", testIPhone); err != nil {
+				t.Fatalf("login() error = %v, want new account created", err)
+			}
+
+			user, err := f.userRepo.FindByZaloID(context.Background(), "zalo-1")
+			if err != nil {
+				t.Fatalf("new user not saved: %v", err)
+			}
+			if user.Phone != tt.wantPhone || user.Email != tt.wantEmail {
+				t.Errorf("new user contact = %q %q, want %q %q", user.Phone, user.Email, tt.wantPhone, tt.wantEmail)
+			}
+			if tt.existing != nil && (tt.existing.Phone == "" || tt.existing.Email == "") {
+				t.Errorf("existing user contact was cleared: %q %q", tt.existing.Phone, tt.existing.Email)
+			}
+		})
+	}
+}
+
+func TestRefreshTokenUseCaseLockedUser(t *testing.T) {
+	f := newLoginFixture()
+	login, err := f.login("203.0.113.7", testIPhone)
+	if err != nil {
+		t.Fatalf("login error = %v", err)
+	}
+	user, _ := f.userRepo.FindByZaloID(context.Background(), "zalo-1")
+	user.Lock("chargeback fraud")
+
+	refresh := NewRefreshTokenUseCase(f.userRepo, f.sessionRepo, f.tokens)
+	_, err = refresh.Execute(context.Background(), &RefreshTokenCommand{RefreshToken: login.RefreshToken})
+	if !errors.Is(err, domain.ErrAccountLocked) {
+		t.Fatalf("Execute() error = %v, want ErrAccountLocked", err)
+	}
+}
+
+func TestLockAndUnlockUserUseCases(t *testing.T) {
+	f := newLoginFixture()
+	for _, device := range []string{testIPhone, testAndroid} {
+		if _, err := f.login("203.0.113.7", device); err != nil {
+			t.Fatalf("login error = %v", err)
+		}
+	}
+	user, _ := f.userRepo.FindByZaloID(context.Background(), "zalo-1")
+	f.publisher.events = nil
+
+	lock := NewLockUserUseCase(f.userRepo, f.sessionRepo, f.publisher)
+	result, err := lock.Execute(context.Background(), NewLockUserCommand(user.GetID(), "chargeback fraud"))
+	if err != nil {
+		t.Fatalf("lock error = %v", err)
+	}
+	if !result.IsLocked || user.LockReason != "chargeback fraud" || user.RefreshToken != "" {
+		t.Errorf("user after lock = %+v, want locked with reason and no refresh token", user)
+	}
+	for _, session := range f.sessionRepo.sessions {
+		if session.IsActive {
+			t.Errorf("session %s still active after lock", session.ID)
+		}
+	}
+	if types := f.publisher.eventTypes(); len(types) != 1 || types[0] != domain.UserLockedEvent {
+		t.Fatalf("events = %v, want [%s]", types, domain.UserLockedEvent)
+	}
+	if event := f.publisher.events[0].(*domain.UserLocked); event.UserID != user.GetID() || event.Reason != "chargeback fraud" {
+		t.Errorf("event = %+v, want user and reason", event)
+	}
+
+	if _, err := f.login("203.0.113.7", testIPhone); !errors.Is(err, domain.ErrAccountLocked) {
+		t.Fatalf("login after lock error = %v, want ErrAccountLocked", err)
+	}
+
+	unlock := NewUnlockUserUseCase(f.userRepo)
+	if result, err := unlock.Execute(context.Background(), NewUnlockUserCommand(user.GetID())); err != nil || result.IsLocked {
+		t.Fatalf("unlock = %+v, %v, want unlocked", result, err)
+	}
+	if _, err := f.login("203.0.113.7", testIPhone); err != nil {
+		t.Errorf("login after unlock error = %v", err)
+	}
+}
+
+func TestLockUserUseCaseUnknownUser(t *testing.T) {
+	lock := NewLockUserUseCase(newInMemoryUserRepository(), &inMemorySessionRepository{}, &recordingEventPublisher{})
+	if _, err := lock.Execute(context.Background(), NewLockUserCommand("missing", "fraud")); !errors.Is(err, sharedDomain.ErrNotFound) {
+		t.Errorf("Execute() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLoginUseCaseSuspiciousLogin(t *testing.T) {
+	type attempt struct {
+		ipAddress  string
+		deviceInfo string
+	}
+	first := attempt{"203.0.113.7", testIPhone}
+
+	tests := []struct {
+		name           string
+		history        []attempt
+		login          attempt
+		failHistory    bool
+		failPublish    bool
+		wantSuspicious bool
+	}{
+		{name: "first-ever login", login: first},
+		{name: "known device and IP", history: []attempt{first}, login: first},
+		{name: "known device, new IP", history: []attempt{first}, login: attempt{"198.51.100.20", testIPhone}, wantSuspicious: true},
+		{name: "new device, known IP", history: []attempt{first}, login: attempt{"203.0.113.7", testAndroid}, wantSuspicious: true},
+		{name: "device and IP seen in different sessions", history: []attempt{first, {"198.51.100.20", testAndroid}}, login: attempt{"198.51.100.20", testIPhone}},
+		{name: "history unavailable", history: []attempt{first}, login: attempt{"198.51.100.20", testAndroid}, failHistory: true},
+		{name: "publish failure does not fail login", history: []attempt{first}, login: attempt{"198.51.100.20", testAndroid}, failPublish: true, wantSuspicious: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newLoginFixture()
+			for _, previous := range tt.history {
+				if _, err := f.login(previous.ipAddress, previous.deviceInfo); err != nil {
+					t.Fatalf("history login error = %v", err)
+				}
+			}
+			f.publisher.events = nil
+			f.publisher.err = nil
+			if tt.failPublish {
+				f.publisher.err = errors.New("kafka: broker not available")
+			}
+			if tt.failHistory {
+				repo := &failingHistorySessionRepository{f.sessionRepo}
+				f.useCase = NewLoginUseCase(f.userRepo, repo, f.zalo, f.tokens, f.limiter, DefaultLoginRateLimits(), f.publisher)
+			}
+
+			result, err := f.login(tt.login.ipAddress, tt.login.deviceInfo)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			session, err := f.sessionRepo.FindByAccessToken(context.Background(), result.AccessToken)
+			if err != nil {
+				t.Fatalf("session not saved: %v", err)
+			}
+			if session.IsSuspicious != tt.wantSuspicious {
+				t.Errorf("IsSuspicious = %v, want %v", session.IsSuspicious, tt.wantSuspicious)
+			}
+
+			wantEvents := 0
+			if tt.wantSuspicious && !tt.failPublish {
+				wantEvents = 1
+			}
+			if types := f.publisher.eventTypes(); len(types) != wantEvents {
+				t.Fatalf("events = %v, want %d %s event(s)", types, wantEvents, domain.SuspiciousLoginEvent)
+			}
+			if wantEvents == 1 {
+				event, ok := f.publisher.events[0].(*domain.SuspiciousLogin)
+				if !ok || event.GetEventType() != domain.SuspiciousLoginEvent || event.SessionID != session.ID.String() ||
+					event.IPAddress != tt.login.ipAddress || event.DeviceInfo != tt.login.deviceInfo {
+					t.Errorf("event = %+v, want suspicious login for the new session", f.publisher.events[0])
+				}
+			}
+		})
+	}
+}
+
+func TestGrantAndRevokeRoleUseCases(t *testing.T) {
+	user := newTestUser(t, "zalo-1", "0901234567", "")
+	repo := newInMemoryUserRepository(user)
+	grant := NewGrantRoleUseCase(repo)
+	revoke := NewRevokeRoleUseCase(repo)
+	ctx := context.Background()
+
+	result, err := grant.Execute(ctx, NewGrantRoleCommand(user.GetID(), domain.RoleDriver))
+	if err != nil {
+		t.Fatalf("grant error = %v", err)
+	}
+	if !user.HasRole(domain.RoleDriver) || len(result.Roles) != 2 {
+		t.Errorf("roles after grant = %v, want passenger and driver", result.Roles)
+	}
+
+	// Granting twice is a no-op
+	if result, err = grant.Execute(ctx, NewGrantRoleCommand(user.GetID(), domain.RoleDriver)); err != nil || len(result.Roles) != 2 {
+		t.Errorf("second grant = %v, %v, want unchanged roles", result.Roles, err)
+	}
+
+	if _, err := grant.Execute(ctx, NewGrantRoleCommand(user.GetID(), "superuser")); !errors.Is(err, sharedDomain.ErrValidation) {
+		t.Errorf("grant invalid role error = %v, want VALIDATION_ERROR", err)
+	}
+	if _, err := grant.Execute(ctx, NewGrantRoleCommand("missing", domain.RoleDriver)); !errors.Is(err, sharedDomain.ErrNotFound) {
+		t.Errorf("grant to missing user error = %v, want NOT_FOUND", err)
+	}
+
+	if result, err = revoke.Execute(ctx, NewRevokeRoleCommand(user.GetID(), domain.RoleDriver)); err != nil {
+		t.Fatalf("revoke error = %v", err)
+	}
+	if user.HasRole(domain.RoleDriver) || len(result.Roles) != 1 {
+		t.Errorf("roles after revoke = %v, want passenger only", result.Roles)
+	}
+}
+
+func TestIntrospectTokenUseCase(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		sessionExpiry time.Duration
+		revoke        bool
+		lock          bool
+		noSession     bool
+		token         string
+		wantActive    bool
+		wantExpiry    time.Duration
+	}{
+		{name: "active, token expires first", sessionExpiry: 24 * time.Hour, wantActive: true, wantExpiry: time.Hour},
+		{name: "active, session expires first", sessionExpiry: 30 * time.Minute, wantActive: true, wantExpiry: 30 * time.Minute},
+		{name: "revoked session", sessionExpiry: 24 * time.Hour, revoke: true},
+		{name: "expired session", sessionExpiry: -time.Minute},
+		{name: "locked user", sessionExpiry: 24 * time.Hour, lock: true},
+		{name: "no session for token", noSession: true},
+		{name: "unknown token", sessionExpiry: 24 * time.Hour, token: "forged"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := newTestUser(t, "zalo-1", "0901234567", "lan@example.com")
+			if tt.lock {
+				user.Lock("fraud review")
+			}
+			tokens := newFakeTokenService()
+			accessToken := tokens.issue("access", user.GetID(), user.Roles)
+
+			sessionRepo := &inMemorySessionRepository{}
+			if !tt.noSession {
+				session := domain.NewAuthSession(user.GetID(), accessToken, "refresh", testIPhone, "10.0.0.1", now.Add(tt.sessionExpiry))
+				if tt.revoke {
+					session.Revoke()
+				}
+				sessionRepo.sessions = append(sessionRepo.sessions, session)
+			}
+
+			token := accessToken
+			if tt.token != "" {
+				token = tt.token
+			}
+			useCase := NewIntrospectTokenUseCase(newInMemoryUserRepository(user), sessionRepo, tokens)
+			result, err := useCase.Execute(context.Background(), NewIntrospectTokenQuery(token))
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.Active != tt.wantActive {
+				t.Fatalf("Active = %v, want %v", result.Active, tt.wantActive)
+			}
+			if !tt.wantActive {
+				if result.UserID != "" || len(result.Roles) != 0 {
+					t.Errorf("inactive result leaks claims: %+v", result)
+				}
+				return
+			}
+			if result.UserID != user.GetID() || len(result.Roles) == 0 {
+				t.Errorf("result = %+v, want user %s with roles", result, user.GetID())
+			}
+			// Allow a second of drift between the fake token clock and the test clock
+			if want := now.Add(tt.wantExpiry).Unix(); result.ExpiresAt < want-1 || result.ExpiresAt > want+1 {
+				t.Errorf("ExpiresAt = %d, want %d", result.ExpiresAt, want)
+			}
+		})
+	}
+}
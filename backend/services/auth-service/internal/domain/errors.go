@@ -0,0 +1,11 @@
+// Package domain contains auth service domain errors
+package domain
+
+import (
+	"github.com/southern-martin/zride/backend/shared/domain"
+)
+
+// Auth specific domain errors
+var (
+	ErrAccountLocked = domain.NewDomainError("ACCOUNT_LOCKED", "Account is locked")
+)
@@ -3,6 +3,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/southern-martin/zride/backend/shared/domain"
 )
@@ -10,7 +11,7 @@ import (
 // UserRepository interface for user data access
 type UserRepository interface {
 	domain.Repository[*User]
-	
+
 	// Custom methods specific to user repository
 	FindByZaloID(ctx context.Context, zaloID string) (*User, error)
 	FindByEmail(ctx context.Context, email string) (*User, error)
@@ -26,11 +27,17 @@ type AuthSessionRepository interface {
 	FindByAccessToken(ctx context.Context, token string) (*AuthSession, error)
 	FindByRefreshToken(ctx context.Context, token string) (*AuthSession, error)
 	FindActiveByUserID(ctx context.Context, userID string) ([]*AuthSession, error)
+	FindRecentByUserID(ctx context.Context, userID string, limit int) ([]*AuthSession, error)
 	RevokeSession(ctx context.Context, sessionID string) error
 	RevokeAllUserSessions(ctx context.Context, userID string) error
 	CleanupExpiredSessions(ctx context.Context) error
 }
 
+// EventPublisher interface for publishing domain events
+type EventPublisher interface {
+	Publish(ctx context.Context, event domain.DomainEvent) error
+}
+
 // ZaloService interface for Zalo integration
 type ZaloService interface {
 	VerifyAccessToken(ctx context.Context, accessToken string) (*ZaloUserInfo, error)
@@ -40,13 +47,26 @@ type ZaloService interface {
 
 // TokenService interface for JWT token management
 type TokenService interface {
-	GenerateAccessToken(userID string) (string, error)
+	GenerateAccessToken(userID string, roles []string) (string, error)
 	GenerateRefreshToken(userID string) (string, error)
 	ValidateAccessToken(token string) (*TokenClaims, error)
 	ValidateRefreshToken(token string) (*TokenClaims, error)
 	RevokeToken(token string) error
 }
 
+// RateLimiter interface for token bucket rate limiting shared across replicas
+type RateLimiter interface {
+	// Allow consumes one token from the bucket identified by key
+	Allow(ctx context.Context, key string, limit RateLimit) (bool, error)
+}
+
+// RateLimit represents token bucket limits: Burst tokens, refilled at Rate tokens per Period
+type RateLimit struct {
+	Rate   int
+	Burst  int
+	Period time.Duration
+}
+
 // ZaloUserInfo represents user info from Zalo
 type ZaloUserInfo struct {
 	ID     string `json:"id"`
@@ -66,19 +86,22 @@ type ZaloTokenResponse struct {
 
 // TokenClaims represents JWT token claims
 type TokenClaims struct {
-	UserID    string `json:"user_id"`
-	ZaloID    string `json:"zalo_id"`
-	TokenType string `json:"token_type"`
-	ExpiresAt int64  `json:"exp"`
-	IssuedAt  int64  `json:"iat"`
+	UserID    string   `json:"user_id"`
+	ZaloID    string   `json:"zalo_id"`
+	TokenType string   `json:"token_type"`
+	Roles     []string `json:"roles,omitempty"`
+	ExpiresAt int64    `json:"exp"`
+	IssuedAt  int64    `json:"iat"`
 }
 
 // Events
 const (
-	UserRegisteredEvent = "user.registered"
-	UserLoggedInEvent   = "user.logged_in"
-	UserLoggedOutEvent  = "user.logged_out"
+	UserRegisteredEvent     = "user.registered"
+	UserLoggedInEvent       = "user.logged_in"
+	UserLoggedOutEvent      = "user.logged_out"
 	UserProfileUpdatedEvent = "user.profile_updated"
+	SuspiciousLoginEvent    = "user.suspicious_login"
+	UserLockedEvent         = "user.locked"
 )
 
 // UserRegistered domain event
@@ -112,4 +135,20 @@ type UserProfileUpdated struct {
 	Name   string `json:"name"`
 	Email  string `json:"email"`
 	Phone  string `json:"phone"`
-}
\ No newline at end of file
+}
+
+// SuspiciousLogin domain event raised when a login comes from a new device or IP
+type SuspiciousLogin struct {
+	*domain.BaseDomainEvent
+	UserID     string `json:"user_id"`
+	SessionID  string `json:"session_id"`
+	DeviceInfo string `json:"device_info"`
+	IPAddress  string `json:"ip_address"`
+}
+
+// UserLocked domain event
+type UserLocked struct {
+	*domain.BaseDomainEvent
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"`
+}
@@ -1,27 +1,38 @@
-package domain
 // Package domain contains auth service domain entities and value objects
 package domain
 
 import (
 	"errors"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/southern-martin/zride/backend/shared/domain"
 )
 
+// User roles
+const (
+	RolePassenger = "passenger"
+	RoleDriver    = "driver"
+	RoleAdmin     = "admin"
+)
+
 // User represents the user aggregate root
 type User struct {
 	domain.Entity
-	ZaloID       string    `json:"zalo_id" db:"zalo_id"`
-	Name         string    `json:"name" db:"name"`
-	Phone        string    `json:"phone" db:"phone"`
-	Email        string    `json:"email" db:"email"`
-	Avatar       string    `json:"avatar" db:"avatar"`
-	IsActive     bool      `json:"is_active" db:"is_active"`
+	ZaloID       string     `json:"zalo_id" db:"zalo_id"`
+	Name         string     `json:"name" db:"name"`
+	Phone        string     `json:"phone" db:"phone"`
+	Email        string     `json:"email" db:"email"`
+	Avatar       string     `json:"avatar" db:"avatar"`
+	Roles        []string   `json:"roles" db:"roles"`
+	IsActive     bool       `json:"is_active" db:"is_active"`
+	IsLocked     bool       `json:"is_locked" db:"is_locked"`
+	LockedAt     *time.Time `json:"locked_at" db:"locked_at"`
+	LockReason   string     `json:"lock_reason" db:"lock_reason"`
 	LastLoginAt  *time.Time `json:"last_login_at" db:"last_login_at"`
-	RefreshToken string    `json:"-" db:"refresh_token"`
-	Version      int       `json:"version" db:"version"`
+	RefreshToken string     `json:"-" db:"refresh_token"`
+	Version      int        `json:"version" db:"version"`
 }
 
 // NewUser creates a new user
@@ -48,9 +59,10 @@ func NewUser(zaloID, name, phone, email, avatar string) (*User, error) {
 		Entity:   domain.NewEntity(),
 		ZaloID:   zaloID,
 		Name:     name,
-		Phone:    phone,
+		Phone:    NormalizePhone(phone),
 		Email:    email,
 		Avatar:   avatar,
+		Roles:    []string{RolePassenger},
 		IsActive: true,
 		Version:  1,
 	}
@@ -91,7 +103,7 @@ func (u *User) UpdateProfile(name, phone, email, avatar string) error {
 	}
 
 	u.Name = name
-	u.Phone = phone
+	u.Phone = NormalizePhone(phone)
 	u.Email = email
 	u.Avatar = avatar
 	u.MarkAsModified()
@@ -131,6 +143,58 @@ func (u *User) Activate() {
 	u.MarkAsModified()
 }
 
+// HasRole checks if user has the given role
+func (u *User) HasRole(role string) bool {
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// GrantRole grants a role to the user
+func (u *User) GrantRole(role string) error {
+	if !isValidRole(role) {
+		return errors.New("invalid role")
+	}
+	if u.HasRole(role) {
+		return nil
+	}
+	u.Roles = append(u.Roles, role)
+	u.MarkAsModified()
+	return nil
+}
+
+// RevokeRole revokes a role from the user
+func (u *User) RevokeRole(role string) {
+	for i, r := range u.Roles {
+		if r == role {
+			u.Roles = append(u.Roles[:i], u.Roles[i+1:]...)
+			u.MarkAsModified()
+			return
+		}
+	}
+}
+
+// Lock locks user account administratively; unlike Deactivate the account still exists
+func (u *User) Lock(reason string) {
+	now := time.Now()
+	u.IsLocked = true
+	u.LockedAt = &now
+	u.LockReason = reason
+	u.ClearRefreshToken()
+	u.MarkAsModified()
+}
+
+// Unlock unlocks user account
+func (u *User) Unlock() {
+	u.IsLocked = false
+	u.LockedAt = nil
+	u.LockReason = ""
+	u.MarkAsModified()
+}
+
 // AuthSession represents an authentication session
 type AuthSession struct {
 	domain.Entity
@@ -141,6 +205,7 @@ type AuthSession struct {
 	IsActive     bool      `json:"is_active" db:"is_active"`
 	DeviceInfo   string    `json:"device_info" db:"device_info"`
 	IPAddress    string    `json:"ip_address" db:"ip_address"`
+	IsSuspicious bool      `json:"is_suspicious" db:"is_suspicious"`
 }
 
 // NewAuthSession creates a new auth session
@@ -168,14 +233,77 @@ func (s *AuthSession) Revoke() {
 	s.UpdateTimestamp()
 }
 
+// Device types derived from session device info
+const (
+	DeviceTypeIOS     = "ios"
+	DeviceTypeAndroid = "android"
+	DeviceTypeWeb     = "web"
+	DeviceTypeOther   = "other"
+)
+
+// DeviceType classifies the session's device info into a coarse device type
+func (s *AuthSession) DeviceType() string {
+	info := strings.ToLower(s.DeviceInfo)
+	switch {
+	case strings.Contains(info, "iphone"), strings.Contains(info, "ipad"), strings.Contains(info, "ios"):
+		return DeviceTypeIOS
+	case strings.Contains(info, "android"):
+		return DeviceTypeAndroid
+	case strings.Contains(info, "mozilla"), strings.Contains(info, "web"), strings.Contains(info, "zalo mini app"):
+		return DeviceTypeWeb
+	default:
+		return DeviceTypeOther
+	}
+}
+
+// IsFromKnownSource checks if the session's device and IP were seen in any of the given sessions
+func (s *AuthSession) IsFromKnownSource(previous []*AuthSession) bool {
+	knownDevice, knownIP := false, false
+	for _, p := range previous {
+		if p.DeviceInfo == s.DeviceInfo {
+			knownDevice = true
+		}
+		if p.IPAddress == s.IPAddress {
+			knownIP = true
+		}
+	}
+	return knownDevice && knownIP
+}
+
+// MarkSuspicious flags the session as originating from a new device or IP
+func (s *AuthSession) MarkSuspicious() {
+	s.IsSuspicious = true
+	s.UpdateTimestamp()
+}
+
 // Utility functions for validation
 func isValidEmail(email string) bool {
 	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 	return emailRegex.MatchString(email)
 }
 
+func isValidRole(role string) bool {
+	return role == RolePassenger || role == RoleDriver || role == RoleAdmin
+}
+
 func isValidPhone(phone string) bool {
 	// Vietnamese phone number format
 	phoneRegex := regexp.MustCompile(`^(\+84|84|0)[0-9]{9,10}$`)
 	return phoneRegex.MatchString(phone)
-}
\ No newline at end of file
+}
+
+// NormalizePhone rewrites a Vietnamese phone number to its national 0-prefixed form,
+// so 0901234567, 84901234567 and +84901234567 are stored and looked up as the same number.
+// Values that are not valid phone numbers are returned unchanged.
+func NormalizePhone(phone string) string {
+	if !isValidPhone(phone) {
+		return phone
+	}
+	if rest, ok := strings.CutPrefix(phone, "+84"); ok {
+		return "0" + rest
+	}
+	if rest, ok := strings.CutPrefix(phone, "84"); ok {
+		return "0" + rest
+	}
+	return phone
+}
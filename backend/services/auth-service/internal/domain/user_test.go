@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuthSessionDeviceType(t *testing.T) {
+	tests := []struct {
+		deviceInfo string
+		want       string
+	}{
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15", DeviceTypeIOS},
+		{"Zalo/23.09 (iPad; iOS 16.5)", DeviceTypeIOS},
+		{"Mozilla/5.0 (Linux; Android 13; SM-A536E) AppleWebKit/537.36", DeviceTypeAndroid},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/118.0", DeviceTypeWeb},
+		{"Zalo Mini App", DeviceTypeWeb},
+		{"curl/8.4.0", DeviceTypeOther},
+		{"", DeviceTypeOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.deviceInfo, func(t *testing.T) {
+			session := NewAuthSession("user-1", "access", "refresh", tt.deviceInfo, "10.0.0.1", time.Now().Add(time.Hour))
+			if got := session.DeviceType(); got != tt.want {
+				t.Errorf("DeviceType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  string
+	}{
+		{"0901234567", "0901234567"},
+		{"+84901234567", "0901234567"},
+		{"84901234567", "0901234567"},
+		{"+842812345678", "02812345678"},
+		{"", ""},
+		{"12345", "12345"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phone, func(t *testing.T) {
+			if got := NormalizePhone(tt.phone); got != tt.want {
+				t.Errorf("NormalizePhone(%q) = %q, want %q", tt.phone, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserStoresNormalizedPhone(t *testing.T) {
+	user, err := NewUser("zalo-1", "Lan", "+84901234567", "", "")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	if user.Phone != "0901234567" {
+		t.Errorf("NewUser() phone = %q, want 0901234567", user.Phone)
+	}
+
+	if err := user.UpdateProfile("Lan", "84907654321", "", ""); err != nil {
+		t.Fatalf("UpdateProfile() error = %v", err)
+	}
+	if user.Phone != "0907654321" {
+		t.Errorf("UpdateProfile() phone = %q, want 0907654321", user.Phone)
+	}
+}
@@ -0,0 +1,90 @@
+// Package infrastructure provides Redis-backed rate limiter implementation
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/southern-martin/zride/backend/services/auth-service/internal/domain"
+)
+
+// tokenBucketScript atomically refills and consumes a token bucket stored as a hash.
+// KEYS[1] bucket key; ARGV: rate, burst, period (ms), now (ms)
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local period = tonumber(ARGV[3])
+local now = tonumber(ARGV[4])
+
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "updated_at")
+local tokens = tonumber(bucket[1])
+local updatedAt = tonumber(bucket[2])
+if tokens == nil then
+	tokens = burst
+	updatedAt = now
+end
+
+local elapsed = math.max(0, now - updatedAt)
+tokens = math.min(burst, tokens + (elapsed * rate / period))
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call("HSET", KEYS[1], "tokens", tokens, "updated_at", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst * period / rate))
+return allowed
+`)
+
+// RedisRateLimiter implements RateLimiter interface
+type RedisRateLimiter struct {
+	client *redis.Client
+	prefix string
+	now    func() time.Time
+}
+
+// NewRedisRateLimiter creates new Redis rate limiter
+func NewRedisRateLimiter(client *redis.Client) domain.RateLimiter {
+	return &RedisRateLimiter{
+		client: client,
+		prefix: "auth:ratelimit:",
+		now:    time.Now,
+	}
+}
+
+// Allow consumes one token from the bucket identified by key
+func (l *RedisRateLimiter) Allow(ctx context.Context, key string, limit domain.RateLimit) (bool, error) {
+	if limit.Rate <= 0 || limit.Burst <= 0 || limit.Period <= 0 {
+		return true, nil
+	}
+
+	return allowedFromResult(tokenBucketScript.Run(ctx, l.client,
+		[]string{l.prefix + key},
+		limit.Rate,
+		limit.Burst,
+		limit.Period.Milliseconds(),
+		l.now().UnixMilli(),
+	))
+}
+
+// allowedFromResult interprets the token bucket script reply: 1 allows, 0 denies.
+// Errors and unexpected replies fail the check rather than allowing the request.
+func allowedFromResult(cmd *redis.Cmd) (bool, error) {
+	result, err := cmd.Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+
+	switch result {
+	case 1:
+		return true, nil
+	case 0:
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to check rate limit: unexpected script result %d", result)
+	}
+}
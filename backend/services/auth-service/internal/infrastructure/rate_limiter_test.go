@@ -0,0 +1,124 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/southern-martin/zride/backend/services/auth-service/internal/domain"
+)
+
+func newTestRateLimiter(t *testing.T) (*RedisRateLimiter, *miniredis.Miniredis, *time.Time) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewRedisRateLimiter(client).(*RedisRateLimiter)
+	limiter.now = func() time.Time { return now }
+	return limiter, server, &now
+}
+
+func TestRedisRateLimiterTokenBucket(t *testing.T) {
+	limiter, server, now := newTestRateLimiter(t)
+	ctx := context.Background()
+	limit := domain.RateLimit{Rate: 1, Burst: 2, Period: time.Second}
+
+	for i, want := range []bool{true, true, false} {
+		allowed, err := limiter.Allow(ctx, "login:ip:203.0.113.7", limit)
+		if err != nil {
+			t.Fatalf("Allow() #%d error = %v", i+1, err)
+		}
+		if allowed != want {
+			t.Errorf("Allow() #%d = %v, want %v", i+1, allowed, want)
+		}
+	}
+
+	// Buckets are independent per key
+	if allowed, _ := limiter.Allow(ctx, "login:ip:203.0.113.8", limit); !allowed {
+		t.Error("Allow() for another key = false, want true")
+	}
+
+	// One period refills one token
+	*now = now.Add(time.Second)
+	if allowed, _ := limiter.Allow(ctx, "login:ip:203.0.113.7", limit); !allowed {
+		t.Error("Allow() after refill = false, want true")
+	}
+	if allowed, _ := limiter.Allow(ctx, "login:ip:203.0.113.7", limit); allowed {
+		t.Error("Allow() after using the refilled token = true, want false")
+	}
+
+	if !server.Exists("auth:ratelimit:login:ip:203.0.113.7") {
+		t.Error("bucket key missing the auth:ratelimit: prefix")
+	}
+	if ttl := server.TTL("auth:ratelimit:login:ip:203.0.113.7"); ttl <= 0 || ttl > 2*time.Second {
+		t.Errorf("bucket TTL = %s, want the time to refill the burst (2s)", ttl)
+	}
+}
+
+func TestRedisRateLimiterBypassesNonPositiveLimits(t *testing.T) {
+	// A nil client proves Redis is never called
+	limiter := &RedisRateLimiter{prefix: "auth:ratelimit:", now: time.Now}
+
+	for _, limit := range []domain.RateLimit{
+		{},
+		{Rate: 0, Burst: 10, Period: time.Minute},
+		{Rate: 5, Burst: 0, Period: time.Minute},
+		{Rate: 5, Burst: 10, Period: 0},
+		{Rate: -1, Burst: 10, Period: time.Minute},
+	} {
+		allowed, err := limiter.Allow(context.Background(), "login:ip:203.0.113.7", limit)
+		if err != nil || !allowed {
+			t.Errorf("Allow(%+v) = %v, %v, want true, nil", limit, allowed, err)
+		}
+	}
+}
+
+func TestRedisRateLimiterFailsClosedWhenRedisIsDown(t *testing.T) {
+	limiter, server, _ := newTestRateLimiter(t)
+	server.Close()
+
+	allowed, err := limiter.Allow(context.Background(), "login:ip:203.0.113.7", domain.RateLimit{Rate: 1, Burst: 1, Period: time.Second})
+	if err == nil || allowed {
+		t.Errorf("Allow() = %v, %v, want false and an error", allowed, err)
+	}
+}
+
+func TestAllowedFromResult(t *testing.T) {
+	redisErr := errors.New("NOSCRIPT No matching script")
+
+	tests := []struct {
+		name        string
+		val         interface{}
+		err         error
+		wantAllowed bool
+		wantErr     bool
+	}{
+		{"allowed", int64(1), nil, true, false},
+		{"denied", int64(0), nil, false, false},
+		{"unexpected number", int64(2), nil, false, true},
+		{"non-integer reply", "OK", nil, false, true},
+		{"nil reply", nil, redis.Nil, false, true},
+		{"redis error", nil, redisErr, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := redis.NewCmd(context.Background())
+			cmd.SetVal(tt.val)
+			cmd.SetErr(tt.err)
+
+			allowed, err := allowedFromResult(cmd)
+			if allowed != tt.wantAllowed || (err != nil) != tt.wantErr {
+				t.Errorf("allowedFromResult() = %v, %v, want %v, error %v", allowed, err, tt.wantAllowed, tt.wantErr)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("allowedFromResult() error = %v, want it to wrap %v", err, tt.err)
+			}
+		})
+	}
+}
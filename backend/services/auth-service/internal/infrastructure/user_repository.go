@@ -5,6 +5,7 @@ package infrastructure
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/southern-martin/zride/backend/shared/infrastructure"
 	sharedDomain "github.com/southern-martin/zride/backend/shared/domain"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // PostgreSQLUserRepository implements UserRepository interface
@@ -28,15 +30,21 @@ func NewPostgreSQLUserRepository(db *infrastructure.Database) domain.UserReposit
 
 // Save saves user to database
 func (r *PostgreSQLUserRepository) Save(ctx context.Context, user *domain.User) error {
+	defer r.ObserveQuery("users.save")()
+
 	query := `
-		INSERT INTO users (id, zalo_id, name, phone, email, avatar, is_active, last_login_at, refresh_token, version, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		INSERT INTO users (id, zalo_id, name, phone, email, avatar, roles, is_active, is_locked, locked_at, lock_reason, last_login_at, refresh_token, version, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		ON CONFLICT (id) DO UPDATE SET
 			name = EXCLUDED.name,
 			phone = EXCLUDED.phone,
 			email = EXCLUDED.email,
 			avatar = EXCLUDED.avatar,
+			roles = EXCLUDED.roles,
 			is_active = EXCLUDED.is_active,
+			is_locked = EXCLUDED.is_locked,
+			locked_at = EXCLUDED.locked_at,
+			lock_reason = EXCLUDED.lock_reason,
 			last_login_at = EXCLUDED.last_login_at,
 			refresh_token = EXCLUDED.refresh_token,
 			version = EXCLUDED.version,
@@ -50,7 +58,11 @@ func (r *PostgreSQLUserRepository) Save(ctx context.Context, user *domain.User)
 		user.Phone,
 		user.Email,
 		user.Avatar,
+		pq.Array(user.Roles),
 		user.IsActive,
+		user.IsLocked,
+		user.LockedAt,
+		user.LockReason,
 		user.LastLoginAt,
 		user.RefreshToken,
 		user.Version,
@@ -58,24 +70,47 @@ func (r *PostgreSQLUserRepository) Save(ctx context.Context, user *domain.User)
 		user.UpdatedAt,
 	)
 
-	return err
+	return mapUniqueViolation(err)
+}
+
+// uniqueViolation is the PostgreSQL error code for a unique constraint violation
+const uniqueViolation pq.ErrorCode = "23505"
+
+// mapUniqueViolation turns a unique constraint violation into a CONFLICT domain error.
+// This catches races the application-level contact checks cannot see.
+func mapUniqueViolation(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != uniqueViolation {
+		return err
+	}
+
+	switch pqErr.Constraint {
+	case "idx_users_phone_active_unique":
+		return sharedDomain.NewDomainError(sharedDomain.ErrConflict.Code, "Phone already in use")
+	case "idx_users_email_active_unique":
+		return sharedDomain.NewDomainError(sharedDomain.ErrConflict.Code, "Email already in use")
+	default:
+		return sharedDomain.NewDomainError(sharedDomain.ErrConflict.Code, "User already exists")
+	}
 }
 
 // FindByID finds user by ID
 func (r *PostgreSQLUserRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	defer r.ObserveQuery("users.find_by_id")()
+
 	userID, err := uuid.Parse(id)
 	if err != nil {
 		return nil, sharedDomain.ErrBadRequest.WithDetails("invalid_user_id", id)
 	}
 
 	query := `
-		SELECT id, zalo_id, name, phone, email, avatar, is_active, last_login_at, refresh_token, version, created_at, updated_at
+		SELECT id, zalo_id, name, phone, email, avatar, roles, is_active, is_locked, locked_at, lock_reason, last_login_at, refresh_token, version, created_at, updated_at
 		FROM users
 		WHERE id = $1 AND is_active = true
 	`
 
 	user := &domain.User{}
-	var lastLoginAt sql.NullTime
+	var lastLoginAt, lockedAt sql.NullTime
 
 	err = r.GetDB().QueryRowContext(ctx, query, userID).Scan(
 		&user.ID,
@@ -84,7 +119,11 @@ func (r *PostgreSQLUserRepository) FindByID(ctx context.Context, id string) (*do
 		&user.Phone,
 		&user.Email,
 		&user.Avatar,
+		pq.Array(&user.Roles),
 		&user.IsActive,
+		&user.IsLocked,
+		&lockedAt,
+		&user.LockReason,
 		&lastLoginAt,
 		&user.RefreshToken,
 		&user.Version,
@@ -103,19 +142,25 @@ func (r *PostgreSQLUserRepository) FindByID(ctx context.Context, id string) (*do
 		user.LastLoginAt = &lastLoginAt.Time
 	}
 
+	if lockedAt.Valid {
+		user.LockedAt = &lockedAt.Time
+	}
+
 	return user, nil
 }
 
 // FindByZaloID finds user by Zalo ID
 func (r *PostgreSQLUserRepository) FindByZaloID(ctx context.Context, zaloID string) (*domain.User, error) {
+	defer r.ObserveQuery("users.find_by_zalo_id")()
+
 	query := `
-		SELECT id, zalo_id, name, phone, email, avatar, is_active, last_login_at, refresh_token, version, created_at, updated_at
+		SELECT id, zalo_id, name, phone, email, avatar, roles, is_active, is_locked, locked_at, lock_reason, last_login_at, refresh_token, version, created_at, updated_at
 		FROM users
 		WHERE zalo_id = $1 AND is_active = true
 	`
 
 	user := &domain.User{}
-	var lastLoginAt sql.NullTime
+	var lastLoginAt, lockedAt sql.NullTime
 
 	err := r.GetDB().QueryRowContext(ctx, query, zaloID).Scan(
 		&user.ID,
@@ -124,7 +169,11 @@ func (r *PostgreSQLUserRepository) FindByZaloID(ctx context.Context, zaloID stri
 		&user.Phone,
 		&user.Email,
 		&user.Avatar,
+		pq.Array(&user.Roles),
 		&user.IsActive,
+		&user.IsLocked,
+		&lockedAt,
+		&user.LockReason,
 		&lastLoginAt,
 		&user.RefreshToken,
 		&user.Version,
@@ -143,19 +192,25 @@ func (r *PostgreSQLUserRepository) FindByZaloID(ctx context.Context, zaloID stri
 		user.LastLoginAt = &lastLoginAt.Time
 	}
 
+	if lockedAt.Valid {
+		user.LockedAt = &lockedAt.Time
+	}
+
 	return user, nil
 }
 
 // FindByEmail finds user by email
 func (r *PostgreSQLUserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	defer r.ObserveQuery("users.find_by_email")()
+
 	query := `
-		SELECT id, zalo_id, name, phone, email, avatar, is_active, last_login_at, refresh_token, version, created_at, updated_at
+		SELECT id, zalo_id, name, phone, email, avatar, roles, is_active, is_locked, locked_at, lock_reason, last_login_at, refresh_token, version, created_at, updated_at
 		FROM users
 		WHERE email = $1 AND is_active = true
 	`
 
 	user := &domain.User{}
-	var lastLoginAt sql.NullTime
+	var lastLoginAt, lockedAt sql.NullTime
 
 	err := r.GetDB().QueryRowContext(ctx, query, email).Scan(
 		&user.ID,
@@ -164,7 +219,11 @@ func (r *PostgreSQLUserRepository) FindByEmail(ctx context.Context, email string
 		&user.Phone,
 		&user.Email,
 		&user.Avatar,
+		pq.Array(&user.Roles),
 		&user.IsActive,
+		&user.IsLocked,
+		&lockedAt,
+		&user.LockReason,
 		&lastLoginAt,
 		&user.RefreshToken,
 		&user.Version,
@@ -183,19 +242,25 @@ func (r *PostgreSQLUserRepository) FindByEmail(ctx context.Context, email string
 		user.LastLoginAt = &lastLoginAt.Time
 	}
 
+	if lockedAt.Valid {
+		user.LockedAt = &lockedAt.Time
+	}
+
 	return user, nil
 }
 
 // FindByPhone finds user by phone
 func (r *PostgreSQLUserRepository) FindByPhone(ctx context.Context, phone string) (*domain.User, error) {
+	defer r.ObserveQuery("users.find_by_phone")()
+
 	query := `
-		SELECT id, zalo_id, name, phone, email, avatar, is_active, last_login_at, refresh_token, version, created_at, updated_at
+		SELECT id, zalo_id, name, phone, email, avatar, roles, is_active, is_locked, locked_at, lock_reason, last_login_at, refresh_token, version, created_at, updated_at
 		FROM users
 		WHERE phone = $1 AND is_active = true
 	`
 
 	user := &domain.User{}
-	var lastLoginAt sql.NullTime
+	var lastLoginAt, lockedAt sql.NullTime
 
 	err := r.GetDB().QueryRowContext(ctx, query, phone).Scan(
 		&user.ID,
@@ -204,7 +269,11 @@ func (r *PostgreSQLUserRepository) FindByPhone(ctx context.Context, phone string
 		&user.Phone,
 		&user.Email,
 		&user.Avatar,
+		pq.Array(&user.Roles),
 		&user.IsActive,
+		&user.IsLocked,
+		&lockedAt,
+		&user.LockReason,
 		&lastLoginAt,
 		&user.RefreshToken,
 		&user.Version,
@@ -223,11 +292,17 @@ func (r *PostgreSQLUserRepository) FindByPhone(ctx context.Context, phone string
 		user.LastLoginAt = &lastLoginAt.Time
 	}
 
+	if lockedAt.Valid {
+		user.LockedAt = &lockedAt.Time
+	}
+
 	return user, nil
 }
 
 // Delete deletes user by ID
 func (r *PostgreSQLUserRepository) Delete(ctx context.Context, id string) error {
+	defer r.ObserveQuery("users.delete")()
+
 	userID, err := uuid.Parse(id)
 	if err != nil {
 		return sharedDomain.ErrBadRequest.WithDetails("invalid_user_id", id)
@@ -254,6 +329,8 @@ func (r *PostgreSQLUserRepository) Delete(ctx context.Context, id string) error
 
 // Exists checks if user exists
 func (r *PostgreSQLUserRepository) Exists(ctx context.Context, id string) (bool, error) {
+	defer r.ObserveQuery("users.exists")()
+
 	userID, err := uuid.Parse(id)
 	if err != nil {
 		return false, sharedDomain.ErrBadRequest.WithDetails("invalid_user_id", id)
@@ -272,6 +349,8 @@ func (r *PostgreSQLUserRepository) Exists(ctx context.Context, id string) (bool,
 
 // UpdateLastLogin updates user's last login timestamp
 func (r *PostgreSQLUserRepository) UpdateLastLogin(ctx context.Context, userID string) error {
+	defer r.ObserveQuery("users.update_last_login")()
+
 	id, err := uuid.Parse(userID)
 	if err != nil {
 		return sharedDomain.ErrBadRequest.WithDetails("invalid_user_id", userID)
@@ -290,6 +369,8 @@ func (r *PostgreSQLUserRepository) UpdateLastLogin(ctx context.Context, userID s
 
 // UpdateRefreshToken updates user's refresh token
 func (r *PostgreSQLUserRepository) UpdateRefreshToken(ctx context.Context, userID, refreshToken string) error {
+	defer r.ObserveQuery("users.update_refresh_token")()
+
 	id, err := uuid.Parse(userID)
 	if err != nil {
 		return sharedDomain.ErrBadRequest.WithDetails("invalid_user_id", userID)
@@ -307,7 +388,9 @@ func (r *PostgreSQLUserRepository) UpdateRefreshToken(ctx context.Context, userI
 
 // FindActiveUsers finds active users with pagination
 func (r *PostgreSQLUserRepository) FindActiveUsers(ctx context.Context, params *sharedDomain.PaginationParams) (*sharedDomain.PaginatedResult[*domain.User], error) {
-	baseQuery := "SELECT id, zalo_id, name, phone, email, avatar, is_active, last_login_at, refresh_token, version, created_at, updated_at FROM users WHERE is_active = true"
+	defer r.ObserveQuery("users.find_active")()
+
+	baseQuery := "SELECT id, zalo_id, name, phone, email, avatar, roles, is_active, is_locked, locked_at, lock_reason, last_login_at, refresh_token, version, created_at, updated_at FROM users WHERE is_active = true"
 	
 	// Get total count
 	countQuery := infrastructure.BuildCountQuery(baseQuery)
@@ -328,7 +411,7 @@ func (r *PostgreSQLUserRepository) FindActiveUsers(ctx context.Context, params *
 	var users []*domain.User
 	for rows.Next() {
 		user := &domain.User{}
-		var lastLoginAt sql.NullTime
+		var lastLoginAt, lockedAt sql.NullTime
 
 		err := rows.Scan(
 			&user.ID,
@@ -337,7 +420,11 @@ func (r *PostgreSQLUserRepository) FindActiveUsers(ctx context.Context, params *
 			&user.Phone,
 			&user.Email,
 			&user.Avatar,
+			pq.Array(&user.Roles),
 			&user.IsActive,
+			&user.IsLocked,
+			&lockedAt,
+			&user.LockReason,
 			&lastLoginAt,
 			&user.RefreshToken,
 			&user.Version,
@@ -352,6 +439,10 @@ func (r *PostgreSQLUserRepository) FindActiveUsers(ctx context.Context, params *
 			user.LastLoginAt = &lastLoginAt.Time
 		}
 
+		if lockedAt.Valid {
+			user.LockedAt = &lockedAt.Time
+		}
+
 		users = append(users, user)
 	}
 
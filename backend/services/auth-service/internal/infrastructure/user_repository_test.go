@@ -0,0 +1,45 @@
+package infrastructure
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+	sharedDomain "github.com/southern-martin/zride/backend/shared/domain"
+)
+
+func TestMapUniqueViolation(t *testing.T) {
+	other := errors.New("pq: connection refused")
+
+	tests := []struct {
+		name         string
+		err          error
+		wantConflict bool
+		wantMessage  string
+	}{
+		{"phone index", &pq.Error{Code: uniqueViolation, Constraint: "idx_users_phone_active_unique"}, true, "Phone already in use"},
+		{"email index", &pq.Error{Code: uniqueViolation, Constraint: "idx_users_email_active_unique"}, true, "Email already in use"},
+		{"zalo id", &pq.Error{Code: uniqueViolation, Constraint: "users_zalo_id_key"}, true, "User already exists"},
+		{"other pq error", &pq.Error{Code: "23502"}, false, ""},
+		{"non-pq error", other, false, ""},
+		{"nil", nil, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mapUniqueViolation(tt.err)
+
+			var domainErr *sharedDomain.DomainError
+			isConflict := errors.As(got, &domainErr) && errors.Is(got, sharedDomain.ErrConflict)
+			if isConflict != tt.wantConflict {
+				t.Fatalf("mapUniqueViolation() = %v, want conflict %v", got, tt.wantConflict)
+			}
+			if tt.wantConflict && domainErr.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", domainErr.Message, tt.wantMessage)
+			}
+			if !tt.wantConflict && got != tt.err {
+				t.Errorf("mapUniqueViolation() = %v, want error passed through", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+// Package handlers contains auth service HTTP handlers
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/southern-martin/zride/backend/services/auth-service/internal/application"
+	"github.com/southern-martin/zride/backend/services/auth-service/internal/domain"
+	sharedDomain "github.com/southern-martin/zride/backend/shared/domain"
+	"github.com/southern-martin/zride/backend/shared/infrastructure"
+)
+
+func init() {
+	// Locked accounts get a specific 403 rather than the generic 500 for unknown codes
+	infrastructure.RegisterErrorStatus(domain.ErrAccountLocked.Code, http.StatusForbidden)
+}
+
+// AuthHandler handles auth HTTP requests
+type AuthHandler struct {
+	*infrastructure.HTTPHandler
+	introspectTokenUseCase   *application.IntrospectTokenUseCase
+	getSessionSummaryUseCase *application.GetSessionSummaryUseCase
+	lockUserUseCase          *application.LockUserUseCase
+	unlockUserUseCase        *application.UnlockUserUseCase
+	grantRoleUseCase         *application.GrantRoleUseCase
+	revokeRoleUseCase        *application.RevokeRoleUseCase
+}
+
+// NewAuthHandler creates new auth handler
+func NewAuthHandler(
+	introspectTokenUseCase *application.IntrospectTokenUseCase,
+	getSessionSummaryUseCase *application.GetSessionSummaryUseCase,
+	lockUserUseCase *application.LockUserUseCase,
+	unlockUserUseCase *application.UnlockUserUseCase,
+	grantRoleUseCase *application.GrantRoleUseCase,
+	revokeRoleUseCase *application.RevokeRoleUseCase,
+) *AuthHandler {
+	return &AuthHandler{
+		HTTPHandler:              infrastructure.NewHTTPHandler(),
+		introspectTokenUseCase:   introspectTokenUseCase,
+		getSessionSummaryUseCase: getSessionSummaryUseCase,
+		lockUserUseCase:          lockUserUseCase,
+		unlockUserUseCase:        unlockUserUseCase,
+		grantRoleUseCase:         grantRoleUseCase,
+		revokeRoleUseCase:        revokeRoleUseCase,
+	}
+}
+
+// RegisterRoutes registers auth routes. User routes are wrapped with authMiddleware, admin routes
+// additionally require the admin role, and internal routes are wrapped with serviceAuthMiddleware
+// (see infrastructure.ServiceAuthMiddleware) so only other services can call them.
+func (h *AuthHandler) RegisterRoutes(
+	mux *http.ServeMux,
+	authMiddleware func(http.Handler) http.Handler,
+	serviceAuthMiddleware func(http.Handler) http.Handler,
+) {
+	requireAdmin := infrastructure.RequireRole(domain.RoleAdmin)
+
+	mux.Handle("/auth/introspect", serviceAuthMiddleware(http.HandlerFunc(h.Introspect)))
+	mux.Handle("/auth/sessions/summary", authMiddleware(http.HandlerFunc(h.GetSessionSummary)))
+	mux.Handle(adminUsersPath, authMiddleware(requireAdmin(http.HandlerFunc(h.AdminUserAction))))
+	mux.Handle(internalUsersPath, serviceAuthMiddleware(http.HandlerFunc(h.InternalUserAction)))
+}
+
+// Admin and internal user route prefixes
+const (
+	adminUsersPath    = "/auth/admin/users/"
+	internalUsersPath = "/auth/internal/users/"
+)
+
+// serviceGrantableRoles are the roles other services may grant, e.g. driver-service after
+// driver registration. Admin can only be granted by an admin.
+var serviceGrantableRoles = map[string]bool{
+	domain.RolePassenger: true,
+	domain.RoleDriver:    true,
+}
+
+// Introspect handles POST /auth/introspect for internal callers
+// The token is read from the Authorization bearer header, or from a {"token": "..."} body.
+func (h *AuthHandler) Introspect(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.WriteError(w, http.StatusMethodNotAllowed, sharedDomain.ErrBadRequest)
+		return
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || token == r.Header.Get("Authorization") {
+		var body struct {
+			Token string `json:"token"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Token == "" {
+			h.WriteValidationError(w, "token is required", nil)
+			return
+		}
+		token = body.Token
+	}
+
+	result, err := h.introspectTokenUseCase.Execute(r.Context(), application.NewIntrospectTokenQuery(token))
+	if err != nil {
+		h.WriteError(w, http.StatusInternalServerError, sharedDomain.ErrInternalError)
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, result)
+}
+
+// GetSessionSummary handles GET /auth/sessions/summary
+func (h *AuthHandler) GetSessionSummary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.WriteError(w, http.StatusMethodNotAllowed, sharedDomain.ErrBadRequest)
+		return
+	}
+
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.WriteError(w, http.StatusUnauthorized, sharedDomain.ErrUnauthorized)
+		return
+	}
+
+	result, err := h.getSessionSummaryUseCase.Execute(r.Context(), application.NewGetSessionSummaryQuery(userID))
+	if err != nil {
+		h.WriteError(w, http.StatusInternalServerError, sharedDomain.ErrInternalError)
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, result)
+}
+
+// AdminUserAction handles admin user routes:
+//
+//	POST   /auth/admin/users/{id}/lock
+//	POST   /auth/admin/users/{id}/unlock
+//	POST   /auth/admin/users/{id}/roles          {"role": "driver"}
+//	DELETE /auth/admin/users/{id}/roles/{role}
+func (h *AuthHandler) AdminUserAction(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, adminUsersPath), "/")
+	if len(parts) < 2 || parts[0] == "" {
+		h.WriteError(w, http.StatusNotFound, sharedDomain.ErrNotFound)
+		return
+	}
+	userID := parts[0]
+
+	switch {
+	case len(parts) == 2 && parts[1] == "lock":
+		if h.requireMethod(w, r, http.MethodPost) {
+			h.lockUser(w, r, userID)
+		}
+	case len(parts) == 2 && parts[1] == "unlock":
+		if h.requireMethod(w, r, http.MethodPost) {
+			h.unlockUser(w, r, userID)
+		}
+	case len(parts) == 2 && parts[1] == "roles":
+		if h.requireMethod(w, r, http.MethodPost) {
+			h.grantRole(w, r, userID, nil)
+		}
+	case len(parts) == 3 && parts[1] == "roles" && parts[2] != "":
+		if h.requireMethod(w, r, http.MethodDelete) {
+			h.revokeRole(w, r, userID, parts[2])
+		}
+	default:
+		h.WriteError(w, http.StatusNotFound, sharedDomain.ErrNotFound)
+	}
+}
+
+// InternalUserAction handles POST /auth/internal/users/{id}/roles for other services,
+// e.g. driver-service granting the driver role once a driver is registered
+func (h *AuthHandler) InternalUserAction(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, internalUsersPath), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] != "roles" {
+		h.WriteError(w, http.StatusNotFound, sharedDomain.ErrNotFound)
+		return
+	}
+
+	if h.requireMethod(w, r, http.MethodPost) {
+		h.grantRole(w, r, parts[0], serviceGrantableRoles)
+	}
+}
+
+// requireMethod writes 405 and returns false unless r uses method
+func (h *AuthHandler) requireMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		h.WriteError(w, http.StatusMethodNotAllowed, sharedDomain.ErrBadRequest)
+		return false
+	}
+	return true
+}
+
+// grantRole grants the role in the request body; a non-nil allowed restricts which roles can be granted
+func (h *AuthHandler) grantRole(w http.ResponseWriter, r *http.Request, userID string, allowed map[string]bool) {
+	var req application.RoleRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Role) == "" {
+		h.WriteValidationError(w, "role is required", nil)
+		return
+	}
+	if allowed != nil && !allowed[req.Role] {
+		h.WriteError(w, http.StatusForbidden, sharedDomain.ErrForbidden)
+		return
+	}
+
+	result, err := h.grantRoleUseCase.Execute(r.Context(), application.NewGrantRoleCommand(userID, req.Role))
+	if err != nil {
+		h.WriteDomainError(w, err)
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, result)
+}
+
+// revokeRole revokes role from the user
+func (h *AuthHandler) revokeRole(w http.ResponseWriter, r *http.Request, userID, role string) {
+	result, err := h.revokeRoleUseCase.Execute(r.Context(), application.NewRevokeRoleCommand(userID, role))
+	if err != nil {
+		h.WriteDomainError(w, err)
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, result)
+}
+
+// lockUser locks the account and revokes its sessions
+func (h *AuthHandler) lockUser(w http.ResponseWriter, r *http.Request, userID string) {
+	var req application.LockUserRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Reason) == "" {
+		h.WriteValidationError(w, "reason is required", nil)
+		return
+	}
+
+	result, err := h.lockUserUseCase.Execute(r.Context(), application.NewLockUserCommand(userID, req.Reason))
+	if err != nil {
+		h.WriteDomainError(w, err)
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, result)
+}
+
+// unlockUser lifts an administrative lock
+func (h *AuthHandler) unlockUser(w http.ResponseWriter, r *http.Request, userID string) {
+	result, err := h.unlockUserUseCase.Execute(r.Context(), application.NewUnlockUserCommand(userID))
+	if err != nil {
+		h.WriteDomainError(w, err)
+		return
+	}
+
+	h.WriteJSON(w, http.StatusOK, result)
+}
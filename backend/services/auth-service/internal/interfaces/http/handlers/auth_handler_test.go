@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/southern-martin/zride/backend/services/auth-service/internal/application"
+	"github.com/southern-martin/zride/backend/services/auth-service/internal/domain"
+	sharedDomain "github.com/southern-martin/zride/backend/shared/domain"
+	"github.com/southern-martin/zride/backend/shared/infrastructure"
+)
+
+func TestAccountLockedMapsToForbidden(t *testing.T) {
+	err := fmt.Errorf("login: %w", domain.ErrAccountLocked)
+	if got := infrastructure.StatusCodeForError(err); got != http.StatusForbidden {
+		t.Errorf("StatusCodeForError(ErrAccountLocked) = %d, want %d", got, http.StatusForbidden)
+	}
+}
+
+// userStore is a minimal UserRepository for handler tests
+type userStore struct {
+	domain.UserRepository
+	users map[string]*domain.User
+}
+
+func (s *userStore) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	if user, ok := s.users[id]; ok {
+		return user, nil
+	}
+	return nil, sharedDomain.ErrNotFound
+}
+
+func (s *userStore) Save(ctx context.Context, user *domain.User) error {
+	s.users[user.GetID()] = user
+	return nil
+}
+
+// asUser stands in for AuthMiddleware, authenticating every request as userID with roles
+func asUser(userID string, roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), "user_id", userID)
+			ctx = context.WithValue(ctx, "user_roles", roles)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func TestRoleRoutes(t *testing.T) {
+	const serviceToken = "s3cret"
+
+	tests := []struct {
+		name         string
+		callerRoles  []string
+		method       string
+		path         string
+		body         string
+		serviceToken string
+		wantStatus   int
+		wantRoles    []string
+	}{
+		{"admin grants driver", []string{domain.RoleAdmin}, http.MethodPost, "/auth/admin/users/{id}/roles", `{"role":"driver"}`, "", http.StatusOK, []string{"passenger", "driver"}},
+		{"admin grants admin", []string{domain.RoleAdmin}, http.MethodPost, "/auth/admin/users/{id}/roles", `{"role":"admin"}`, "", http.StatusOK, []string{"passenger", "admin"}},
+		{"admin grants unknown role", []string{domain.RoleAdmin}, http.MethodPost, "/auth/admin/users/{id}/roles", `{"role":"root"}`, "", http.StatusBadRequest, []string{"passenger"}},
+		{"admin revokes passenger", []string{domain.RoleAdmin}, http.MethodDelete, "/auth/admin/users/{id}/roles/passenger", "", "", http.StatusOK, []string{}},
+		{"admin uses wrong method", []string{domain.RoleAdmin}, http.MethodGet, "/auth/admin/users/{id}/roles", "", "", http.StatusMethodNotAllowed, []string{"passenger"}},
+		{"admin grants to unknown user", []string{domain.RoleAdmin}, http.MethodPost, "/auth/admin/users/missing/roles", `{"role":"driver"}`, "", http.StatusNotFound, []string{"passenger"}},
+		{"non-admin is forbidden", []string{domain.RolePassenger, domain.RoleDriver}, http.MethodPost, "/auth/admin/users/{id}/roles", `{"role":"admin"}`, "", http.StatusForbidden, []string{"passenger"}},
+		{"service grants driver", nil, http.MethodPost, "/auth/internal/users/{id}/roles", `{"role":"driver"}`, serviceToken, http.StatusOK, []string{"passenger", "driver"}},
+		{"service cannot grant admin", nil, http.MethodPost, "/auth/internal/users/{id}/roles", `{"role":"admin"}`, serviceToken, http.StatusForbidden, []string{"passenger"}},
+		{"internal route needs service token", nil, http.MethodPost, "/auth/internal/users/{id}/roles", `{"role":"driver"}`, "", http.StatusUnauthorized, []string{"passenger"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := domain.NewUser("zalo-1", "Nguyen Van Lan", "", "", "")
+			if err != nil {
+				t.Fatalf("NewUser() error = %v", err)
+			}
+			repo := &userStore{users: map[string]*domain.User{user.GetID(): user}}
+			handler := NewAuthHandler(nil, nil, nil, nil, application.NewGrantRoleUseCase(repo), application.NewRevokeRoleUseCase(repo))
+
+			mux := http.NewServeMux()
+			handler.RegisterRoutes(mux, asUser("caller", tt.callerRoles...), infrastructure.ServiceAuthMiddleware(serviceToken))
+
+			req := httptest.NewRequest(tt.method, strings.Replace(tt.path, "{id}", user.GetID(), 1), strings.NewReader(tt.body))
+			if tt.serviceToken != "" {
+				req.Header.Set(infrastructure.ServiceTokenHeader, tt.serviceToken)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if strings.Join(user.Roles, ",") != strings.Join(tt.wantRoles, ",") {
+				t.Errorf("roles = %v, want %v", user.Roles, tt.wantRoles)
+			}
+			if rec.Code == http.StatusOK {
+				var result application.UserDTO
+				if err := json.NewDecoder(rec.Body).Decode(&result); err != nil || strings.Join(result.Roles, ",") != strings.Join(tt.wantRoles, ",") {
+					t.Errorf("response roles = %v (%v), want %v", result.Roles, err, tt.wantRoles)
+				}
+			}
+		})
+	}
+}
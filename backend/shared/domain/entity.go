@@ -38,7 +38,7 @@ type ValueObject interface {
 
 // AggregateRoot represents a domain aggregate root
 type AggregateRoot interface {
-	GetID() uuid.UUID
+	GetID() string
 	GetVersion() int
 	MarkAsModified()
 }
@@ -5,8 +5,8 @@ import "fmt"
 
 // DomainError represents domain-specific errors
 type DomainError struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
 	Details map[string]interface{} `json:"details,omitempty"`
 }
 
@@ -23,23 +23,39 @@ func NewDomainError(code, message string) *DomainError {
 	}
 }
 
-// WithDetails adds details to the domain error
+// WithDetails returns a copy of the domain error with the detail added.
+// The receiver is left untouched, so details never leak into the shared error values below.
 func (e *DomainError) WithDetails(key string, value interface{}) *DomainError {
-	if e.Details == nil {
-		e.Details = make(map[string]interface{})
+	details := make(map[string]interface{}, len(e.Details)+1)
+	for k, v := range e.Details {
+		details[k] = v
 	}
-	e.Details[key] = value
-	return e
+	details[key] = value
+
+	return &DomainError{
+		Code:    e.Code,
+		Message: e.Message,
+		Details: details,
+	}
+}
+
+// Is reports whether target is a domain error with the same code, so errors.Is(err, ErrNotFound)
+// matches copies made by WithDetails and errors built per call with NewDomainError
+func (e *DomainError) Is(target error) bool {
+	t, ok := target.(*DomainError)
+	return ok && t.Code == e.Code
 }
 
 // Common domain errors
 var (
-	ErrNotFound          = NewDomainError("NOT_FOUND", "Resource not found")
-	ErrUnauthorized      = NewDomainError("UNAUTHORIZED", "Unauthorized access")
-	ErrForbidden         = NewDomainError("FORBIDDEN", "Access forbidden")
-	ErrValidation        = NewDomainError("VALIDATION_ERROR", "Validation failed")
-	ErrConflict          = NewDomainError("CONFLICT", "Resource conflict")
-	ErrInternalError     = NewDomainError("INTERNAL_ERROR", "Internal server error")
-	ErrBadRequest        = NewDomainError("BAD_REQUEST", "Bad request")
+	ErrNotFound           = NewDomainError("NOT_FOUND", "Resource not found")
+	ErrUnauthorized       = NewDomainError("UNAUTHORIZED", "Unauthorized access")
+	ErrForbidden          = NewDomainError("FORBIDDEN", "Access forbidden")
+	ErrValidation         = NewDomainError("VALIDATION_ERROR", "Validation failed")
+	ErrConflict           = NewDomainError("CONFLICT", "Resource conflict")
+	ErrInternalError      = NewDomainError("INTERNAL_ERROR", "Internal server error")
+	ErrBadRequest         = NewDomainError("BAD_REQUEST", "Bad request")
 	ErrServiceUnavailable = NewDomainError("SERVICE_UNAVAILABLE", "Service unavailable")
-)
\ No newline at end of file
+	ErrTooManyRequests    = NewDomainError("TOO_MANY_REQUESTS", "Too many requests")
+	ErrPayloadTooLarge    = NewDomainError("PAYLOAD_TOO_LARGE", "Request body too large")
+)
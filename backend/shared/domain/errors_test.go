@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestWithDetailsReturnsCopy(t *testing.T) {
+	base := NewDomainError("NOT_FOUND", "Resource not found")
+
+	withPhone := base.WithDetails("phone", "0901234567")
+	withBoth := withPhone.WithDetails("user_id", "42")
+
+	if len(base.Details) != 0 {
+		t.Errorf("base details = %v, want none", base.Details)
+	}
+	if len(withPhone.Details) != 1 || len(withBoth.Details) != 2 {
+		t.Errorf("details = %v and %v, want 1 and 2 entries", withPhone.Details, withBoth.Details)
+	}
+}
+
+func TestWithDetailsConcurrentUse(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_ = ErrNotFound.WithDetails("user_id", i)
+		}(i)
+	}
+	wg.Wait()
+
+	if len(ErrNotFound.Details) != 0 {
+		t.Errorf("ErrNotFound details = %v, want none", ErrNotFound.Details)
+	}
+}
+
+func TestDomainErrorIsMatchesCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{"copy with details", ErrNotFound.WithDetails("phone", "0901234567"), ErrNotFound, true},
+		{"built per call", NewDomainError("CONFLICT", "Phone already in use"), ErrConflict, true},
+		{"wrapped", fmt.Errorf("find user: %w", ErrNotFound.WithDetails("user_id", "42")), ErrNotFound, true},
+		{"different code", ErrConflict, ErrNotFound, false},
+		{"plain error", errors.New("NOT_FOUND: Resource not found"), ErrNotFound, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.target); got != tt.want {
+				t.Errorf("errors.Is() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Package geohash provides geohash encoding used to shard cache keys, surge cells and per-area locks
+package geohash
+
+import (
+	"fmt"
+	"strings"
+)
+
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Precision bounds and default. Precision 6 cells are roughly 1.2km x 0.6km.
+const (
+	MinPrecision     = 1
+	MaxPrecision     = 12
+	DefaultPrecision = 6
+)
+
+// Encode encodes a coordinate as a geohash of the given precision (number of characters).
+// Precision is clamped to [MinPrecision, MaxPrecision].
+func Encode(lat, lng float64, precision int) string {
+	if precision < MinPrecision {
+		precision = MinPrecision
+	}
+	if precision > MaxPrecision {
+		precision = MaxPrecision
+	}
+
+	latRange := [2]float64{-90, 90}
+	lngRange := [2]float64{-180, 180}
+
+	var hash strings.Builder
+	hash.Grow(precision)
+
+	bit, ch := 0, 0
+	evenBit := true // longitude first
+	for hash.Len() < precision {
+		if evenBit {
+			mid := (lngRange[0] + lngRange[1]) / 2
+			if lng >= mid {
+				ch = ch<<1 | 1
+				lngRange[0] = mid
+			} else {
+				ch <<= 1
+				lngRange[1] = mid
+			}
+		} else {
+			mid := (latRange[0] + latRange[1]) / 2
+			if lat >= mid {
+				ch = ch<<1 | 1
+				latRange[0] = mid
+			} else {
+				ch <<= 1
+				latRange[1] = mid
+			}
+		}
+		evenBit = !evenBit
+
+		bit++
+		if bit == 5 {
+			hash.WriteByte(base32[ch])
+			bit, ch = 0, 0
+		}
+	}
+
+	return hash.String()
+}
+
+// Bounds returns the cell bounding box of a geohash as min/max latitude and longitude
+func Bounds(hash string) (minLat, minLng, maxLat, maxLng float64, err error) {
+	if hash == "" {
+		return 0, 0, 0, 0, fmt.Errorf("empty geohash")
+	}
+
+	latRange := [2]float64{-90, 90}
+	lngRange := [2]float64{-180, 180}
+	evenBit := true
+
+	for _, c := range strings.ToLower(hash) {
+		idx := strings.IndexRune(base32, c)
+		if idx < 0 {
+			return 0, 0, 0, 0, fmt.Errorf("invalid geohash character %q", c)
+		}
+		for n := 4; n >= 0; n-- {
+			bitSet := (idx>>n)&1 == 1
+			if evenBit {
+				mid := (lngRange[0] + lngRange[1]) / 2
+				if bitSet {
+					lngRange[0] = mid
+				} else {
+					lngRange[1] = mid
+				}
+			} else {
+				mid := (latRange[0] + latRange[1]) / 2
+				if bitSet {
+					latRange[0] = mid
+				} else {
+					latRange[1] = mid
+				}
+			}
+			evenBit = !evenBit
+		}
+	}
+
+	return latRange[0], lngRange[0], latRange[1], lngRange[1], nil
+}
+
+// Center returns the center coordinate of a geohash cell
+func Center(hash string) (lat, lng float64, err error) {
+	minLat, minLng, maxLat, maxLng, err := Bounds(hash)
+	if err != nil {
+		return 0, 0, err
+	}
+	return (minLat + maxLat) / 2, (minLng + maxLng) / 2, nil
+}
+
+// Key builds a namespaced key (e.g. "match:w3gv2c") for caches and per-area locks
+func Key(namespace string, lat, lng float64, precision int) string {
+	return namespace + ":" + Encode(lat, lng, precision)
+}
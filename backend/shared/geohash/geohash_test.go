@@ -0,0 +1,54 @@
+package geohash
+
+import "testing"
+
+func TestEncodeKnownVector(t *testing.T) {
+	if got := Encode(57.64911, 10.40744, 11); got != "u4pruydqqvj" {
+		t.Errorf("Encode() = %q, want %q", got, "u4pruydqqvj")
+	}
+}
+
+func TestEncodeNearbyPointsShareCell(t *testing.T) {
+	// Two points ~30m apart in District 1, Ho Chi Minh City
+	a := Encode(10.7769, 106.7009, DefaultPrecision)
+	b := Encode(10.7771, 106.7011, DefaultPrecision)
+	if a != b {
+		t.Errorf("nearby points encoded to %q and %q, want same cell", a, b)
+	}
+
+	// Hanoi is a different cell even at coarse precision
+	if hanoi := Encode(21.0285, 105.8542, 3); hanoi == Encode(10.7769, 106.7009, 3) {
+		t.Errorf("distant points share cell %q", hanoi)
+	}
+}
+
+func TestEncodeClampsPrecision(t *testing.T) {
+	if got := len(Encode(10.7769, 106.7009, 0)); got != MinPrecision {
+		t.Errorf("len(Encode(precision 0)) = %d, want %d", got, MinPrecision)
+	}
+	if got := len(Encode(10.7769, 106.7009, 20)); got != MaxPrecision {
+		t.Errorf("len(Encode(precision 20)) = %d, want %d", got, MaxPrecision)
+	}
+}
+
+func TestBoundsContainsEncodedPoint(t *testing.T) {
+	lat, lng := 10.7769, 106.7009
+	minLat, minLng, maxLat, maxLng, err := Bounds(Encode(lat, lng, 7))
+	if err != nil {
+		t.Fatalf("Bounds() error = %v", err)
+	}
+	if lat < minLat || lat > maxLat || lng < minLng || lng > maxLng {
+		t.Errorf("point (%v, %v) outside bounds [%v, %v] x [%v, %v]", lat, lng, minLat, maxLat, minLng, maxLng)
+	}
+}
+
+func TestBoundsInvalid(t *testing.T) {
+	for _, hash := range []string{"", "u4pa", "u4p!"} {
+		if _, _, _, _, err := Bounds(hash); err == nil {
+			t.Errorf("Bounds(%q) error = nil, want error", hash)
+		}
+		if _, _, err := Center(hash); err == nil {
+			t.Errorf("Center(%q) error = nil, want error", hash)
+		}
+	}
+}
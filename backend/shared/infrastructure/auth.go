@@ -0,0 +1,225 @@
+// Package infrastructure provides token introspection client and authentication middleware
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/southern-martin/zride/backend/shared/domain"
+)
+
+// ServiceTokenHeader carries the shared service credential on internal service-to-service calls
+const ServiceTokenHeader = "X-Service-Token"
+
+// IntrospectionConfig holds auth-service introspection client configuration
+type IntrospectionConfig struct {
+	AuthServiceURL string
+	ServiceToken   string
+	Timeout        time.Duration
+	CacheTTL       time.Duration
+	MaxCacheSize   int
+}
+
+// NewIntrospectionConfig creates introspection config with defaults
+func NewIntrospectionConfig(authServiceURL, serviceToken string) *IntrospectionConfig {
+	return &IntrospectionConfig{
+		AuthServiceURL: authServiceURL,
+		ServiceToken:   serviceToken,
+		Timeout:        2 * time.Second,
+		CacheTTL:       30 * time.Second,
+		MaxCacheSize:   10000,
+	}
+}
+
+// TokenIntrospection represents auth-service introspection result
+type TokenIntrospection struct {
+	Active    bool     `json:"active"`
+	UserID    string   `json:"user_id,omitempty"`
+	ZaloID    string   `json:"zalo_id,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+}
+
+type introspectionCacheEntry struct {
+	result    *TokenIntrospection
+	expiresAt time.Time
+}
+
+// TokenIntrospector validates bearer tokens against auth-service with a short in-memory cache
+type TokenIntrospector struct {
+	config *IntrospectionConfig
+	client *http.Client
+
+	mu    sync.Mutex
+	cache map[string]introspectionCacheEntry
+	now   func() time.Time
+}
+
+// NewTokenIntrospector creates new token introspector
+func NewTokenIntrospector(config *IntrospectionConfig) *TokenIntrospector {
+	return &TokenIntrospector{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+		cache:  make(map[string]introspectionCacheEntry),
+		now:    time.Now,
+	}
+}
+
+// Introspect returns the introspection result for token, served from cache when fresh
+func (i *TokenIntrospector) Introspect(ctx context.Context, token string) (*TokenIntrospection, error) {
+	if result, ok := i.fromCache(token); ok {
+		return result, nil
+	}
+
+	body, err := json.Marshal(map[string]string{"token": token})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode introspection request: %w", err)
+	}
+
+	url := strings.TrimRight(i.config.AuthServiceURL, "/") + "/auth/introspect"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build introspection request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(ServiceTokenHeader, i.config.ServiceToken)
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call auth service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("auth service returned status %d", resp.StatusCode)
+	}
+
+	var result TokenIntrospection
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode introspection response: %w", err)
+	}
+
+	if result.Active {
+		i.store(token, &result)
+	}
+
+	return &result, nil
+}
+
+// fromCache returns a cached, unexpired introspection result
+func (i *TokenIntrospector) fromCache(token string) (*TokenIntrospection, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	entry, ok := i.cache[token]
+	if !ok {
+		return nil, false
+	}
+	if !i.now().Before(entry.expiresAt) {
+		delete(i.cache, token)
+		return nil, false
+	}
+	return entry.result, true
+}
+
+// store caches result until the cache TTL or token expiry, whichever is sooner
+func (i *TokenIntrospector) store(token string, result *TokenIntrospection) {
+	expiresAt := i.now().Add(i.config.CacheTTL)
+	if result.ExpiresAt > 0 {
+		if tokenExpiry := time.Unix(result.ExpiresAt, 0); tokenExpiry.Before(expiresAt) {
+			expiresAt = tokenExpiry
+		}
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if len(i.cache) >= i.config.MaxCacheSize {
+		now := i.now()
+		for key, entry := range i.cache {
+			if !now.Before(entry.expiresAt) {
+				delete(i.cache, key)
+			}
+		}
+		if len(i.cache) >= i.config.MaxCacheSize {
+			i.cache = make(map[string]introspectionCacheEntry)
+		}
+	}
+
+	i.cache[token] = introspectionCacheEntry{result: result, expiresAt: expiresAt}
+}
+
+// AuthMiddleware authenticates requests by introspecting the bearer token.
+// It fails closed: requests are rejected when auth-service cannot be reached.
+func AuthMiddleware(introspector *TokenIntrospector) func(http.Handler) http.Handler {
+	h := NewHTTPHandler()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authHeader := r.Header.Get("Authorization")
+			token := strings.TrimPrefix(authHeader, "Bearer ")
+			if token == "" || token == authHeader {
+				h.WriteError(w, http.StatusUnauthorized, domain.ErrUnauthorized)
+				return
+			}
+
+			result, err := introspector.Introspect(r.Context(), token)
+			if err != nil {
+				h.WriteError(w, http.StatusServiceUnavailable, domain.ErrServiceUnavailable)
+				return
+			}
+			if !result.Active {
+				h.WriteError(w, http.StatusUnauthorized, domain.ErrUnauthorized)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), "user_id", result.UserID)
+			ctx = context.WithValue(ctx, "user_roles", result.Roles)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ServiceAuthMiddleware restricts internal endpoints to callers presenting serviceToken in
+// ServiceTokenHeader. An empty serviceToken rejects every request so a missing config fails closed.
+func ServiceAuthMiddleware(serviceToken string) func(http.Handler) http.Handler {
+	h := NewHTTPHandler()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			provided := r.Header.Get(ServiceTokenHeader)
+			if serviceToken == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(serviceToken)) != 1 {
+				h.WriteError(w, http.StatusUnauthorized, domain.ErrUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireRole allows the request only when the authenticated user has role.
+// It must be chained after AuthMiddleware.
+func RequireRole(role string) func(http.Handler) http.Handler {
+	h := NewHTTPHandler()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, err := h.GetUserIDFromContext(r); err != nil {
+				h.WriteError(w, http.StatusUnauthorized, domain.ErrUnauthorized)
+				return
+			}
+			if !h.HasRole(r, role) {
+				h.WriteError(w, http.StatusForbidden, domain.ErrForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServiceAuthMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name       string
+		configured string
+		provided   string
+		want       int
+	}{
+		{"matching token", "s3cret", "s3cret", http.StatusOK},
+		{"wrong token", "s3cret", "guess", http.StatusUnauthorized},
+		{"missing token", "s3cret", "", http.StatusUnauthorized},
+		{"unconfigured fails closed", "", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/auth/introspect", nil)
+			if tt.provided != "" {
+				req.Header.Set(ServiceTokenHeader, tt.provided)
+			}
+			rec := httptest.NewRecorder()
+
+			ServiceAuthMiddleware(tt.configured)(next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenIntrospectorSendsServiceToken(t *testing.T) {
+	authService := httptest.NewServer(ServiceAuthMiddleware("s3cret")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(TokenIntrospection{Active: true, UserID: "user-1"})
+	})))
+	defer authService.Close()
+
+	introspector := NewTokenIntrospector(NewIntrospectionConfig(authService.URL, "s3cret"))
+	result, err := introspector.Introspect(context.Background(), "access-token")
+	if err != nil {
+		t.Fatalf("Introspect() error = %v", err)
+	}
+	if !result.Active || result.UserID != "user-1" {
+		t.Errorf("Introspect() = %+v, want active user-1", result)
+	}
+}
+
+// fakeAuthService serves /auth/introspect from a token table and counts calls
+type fakeAuthService struct {
+	*httptest.Server
+	calls   int
+	results map[string]TokenIntrospection
+}
+
+func newFakeAuthService(t *testing.T, results map[string]TokenIntrospection) *fakeAuthService {
+	t.Helper()
+	s := &fakeAuthService{results: results}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.calls++
+		var body struct {
+			Token string `json:"token"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		json.NewEncoder(w).Encode(s.results[body.Token])
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	authService := newFakeAuthService(t, map[string]TokenIntrospection{
+		"valid":   {Active: true, UserID: "user-1", Roles: []string{"passenger", "driver"}},
+		"revoked": {Active: false},
+	})
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantCalls     int
+	}{
+		{"active token", "Bearer valid", http.StatusOK, 1},
+		{"inactive token", "Bearer revoked", http.StatusUnauthorized, 1},
+		{"missing header", "", http.StatusUnauthorized, 0},
+		{"non-bearer header", "Basic dXNlcjpwYXNz", http.StatusUnauthorized, 0},
+		{"empty bearer token", "Bearer ", http.StatusUnauthorized, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authService.calls = 0
+			introspector := NewTokenIntrospector(NewIntrospectionConfig(authService.URL, "s3cret"))
+
+			var gotUserID string
+			var gotRoles []string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				h := NewHTTPHandler()
+				gotUserID, _ = h.GetUserIDFromContext(r)
+				gotRoles = h.GetUserRolesFromContext(r)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/trips", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			AuthMiddleware(introspector)(next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if authService.calls != tt.wantCalls {
+				t.Errorf("auth service calls = %d, want %d", authService.calls, tt.wantCalls)
+			}
+			if tt.wantStatus == http.StatusOK && (gotUserID != "user-1" || strings.Join(gotRoles, ",") != "passenger,driver") {
+				t.Errorf("context user_id = %q roles = %v, want user-1 [passenger driver]", gotUserID, gotRoles)
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareFailsClosed(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	for name, url := range map[string]string{"unreachable": down.URL, "server error": failing.URL} {
+		t.Run(name, func(t *testing.T) {
+			called := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true })
+
+			req := httptest.NewRequest(http.MethodGet, "/trips", nil)
+			req.Header.Set("Authorization", "Bearer valid")
+			rec := httptest.NewRecorder()
+			AuthMiddleware(NewTokenIntrospector(NewIntrospectionConfig(url, "s3cret")))(next).ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusServiceUnavailable || called {
+				t.Errorf("status = %d, handler called = %v, want 503 and not called", rec.Code, called)
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		roles      []string
+		wantStatus int
+	}{
+		{"no user", "", nil, http.StatusUnauthorized},
+		{"wrong role", "user-1", []string{"passenger"}, http.StatusForbidden},
+		{"no roles", "user-1", nil, http.StatusForbidden},
+		{"required role", "user-1", []string{"passenger", "driver"}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/trips/1/accept", nil)
+			if tt.userID != "" {
+				ctx := context.WithValue(req.Context(), "user_id", tt.userID)
+				req = req.WithContext(context.WithValue(ctx, "user_roles", tt.roles))
+			}
+			rec := httptest.NewRecorder()
+			RequireRole("driver")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestTokenIntrospectorCache(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	authService := newFakeAuthService(t, map[string]TokenIntrospection{
+		// Token expires well before the 30s cache TTL
+		"short-lived": {Active: true, UserID: "user-1", ExpiresAt: now.Add(10 * time.Second).Unix()},
+		"long-lived":  {Active: true, UserID: "user-2", ExpiresAt: now.Add(time.Hour).Unix()},
+		"revoked":     {Active: false},
+	})
+
+	introspector := NewTokenIntrospector(NewIntrospectionConfig(authService.URL, "s3cret"))
+	introspector.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	introspect := func(token string) *TokenIntrospection {
+		t.Helper()
+		result, err := introspector.Introspect(ctx, token)
+		if err != nil {
+			t.Fatalf("Introspect(%s) error = %v", token, err)
+		}
+		return result
+	}
+
+	introspect("short-lived")
+	introspect("short-lived")
+	if authService.calls != 1 {
+		t.Fatalf("calls after repeat = %d, want 1 (cached)", authService.calls)
+	}
+
+	// At token expiry the cached positive result must not be served, even though the TTL has not passed
+	now = now.Add(10 * time.Second)
+	authService.results["short-lived"] = TokenIntrospection{Active: false}
+	if result := introspect("short-lived"); result.Active {
+		t.Error("expired token served as active from cache")
+	}
+	if authService.calls != 2 {
+		t.Errorf("calls after token expiry = %d, want 2", authService.calls)
+	}
+
+	// Long-lived tokens are re-checked once the cache TTL passes
+	introspect("long-lived")
+	now = now.Add(31 * time.Second)
+	introspect("long-lived")
+	if authService.calls != 4 {
+		t.Errorf("calls after cache TTL = %d, want 4", authService.calls)
+	}
+
+	// Negative results are never cached
+	introspect("revoked")
+	introspect("revoked")
+	if authService.calls != 6 {
+		t.Errorf("calls for inactive token = %d, want 6", authService.calls)
+	}
+}
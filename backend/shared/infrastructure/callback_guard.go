@@ -0,0 +1,186 @@
+// Package infrastructure provides protection for public webhook routes such as payment gateway callbacks
+package infrastructure
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/southern-martin/zride/backend/shared/domain"
+)
+
+// CallbackGuardConfig holds limits for a public callback route
+type CallbackGuardConfig struct {
+	// AllowedSources are the gateway's published ranges, as CIDRs or single IPs
+	AllowedSources []string
+	// ClientIPHeader, if set, is read for the source IP instead of RemoteAddr.
+	// Only set it when the header is written by our own ingress, never by clients.
+	ClientIPHeader string
+	// MaxBodyBytes caps the callback payload; larger bodies are rejected with 413
+	MaxBodyBytes int64
+	// Rate and Burst size a per-source token bucket refilled at Rate requests per Period.
+	// These are separate from user-facing limits and local to each replica.
+	Rate   int
+	Burst  int
+	Period time.Duration
+}
+
+// NewCallbackGuardConfig creates callback guard config with defaults for allowedSources
+func NewCallbackGuardConfig(allowedSources ...string) *CallbackGuardConfig {
+	return &CallbackGuardConfig{
+		AllowedSources: allowedSources,
+		MaxBodyBytes:   64 << 10, // 64KB
+		Rate:           10,
+		Burst:          20,
+		Period:         time.Second,
+	}
+}
+
+// CallbackGuard rejects callback requests from non-allowlisted sources, over the rate limit,
+// or with oversized bodies
+type CallbackGuard struct {
+	*HTTPHandler
+	config   *CallbackGuardConfig
+	networks []*net.IPNet
+
+	mu      sync.Mutex
+	buckets map[string]*callbackBucket
+	now     func() time.Time
+}
+
+type callbackBucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// NewCallbackGuard creates new callback guard, failing on malformed allowlist entries
+func NewCallbackGuard(config *CallbackGuardConfig) (*CallbackGuard, error) {
+	networks := make([]*net.IPNet, 0, len(config.AllowedSources))
+	for _, source := range config.AllowedSources {
+		network, err := parseSource(source)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+
+	return &CallbackGuard{
+		HTTPHandler: NewHTTPHandler(),
+		config:      config,
+		networks:    networks,
+		buckets:     make(map[string]*callbackBucket),
+		now:         time.Now,
+	}, nil
+}
+
+// Middleware guards next. Checks run allowlist first, so only gateway IPs ever get a rate limit bucket.
+func (g *CallbackGuard) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := g.clientIP(r)
+		if ip == nil || !g.isAllowed(ip) {
+			g.WriteError(w, http.StatusForbidden, domain.ErrForbidden)
+			return
+		}
+
+		if !g.allow(ip.String()) {
+			g.WriteError(w, http.StatusTooManyRequests, domain.ErrTooManyRequests)
+			return
+		}
+
+		// Buffer the capped body so oversized payloads are rejected before the handler runs
+		// and the handler can still read the raw bytes, e.g. for signature verification
+		if r.ContentLength > g.config.MaxBodyBytes {
+			g.WriteError(w, http.StatusRequestEntityTooLarge, domain.ErrPayloadTooLarge)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, g.config.MaxBodyBytes+1))
+		if err != nil {
+			g.WriteError(w, http.StatusBadRequest, domain.ErrBadRequest)
+			return
+		}
+		if int64(len(body)) > g.config.MaxBodyBytes {
+			g.WriteError(w, http.StatusRequestEntityTooLarge, domain.ErrPayloadTooLarge)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the source IP of r, or nil if it can't be parsed
+func (g *CallbackGuard) clientIP(r *http.Request) net.IP {
+	if g.config.ClientIPHeader != "" {
+		return net.ParseIP(strings.TrimSpace(r.Header.Get(g.config.ClientIPHeader)))
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+// isAllowed checks if ip is inside any allowlisted network
+func (g *CallbackGuard) isAllowed(ip net.IP) bool {
+	for _, network := range g.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// allow consumes one token from the bucket of source
+func (g *CallbackGuard) allow(source string) bool {
+	if g.config.Rate <= 0 || g.config.Burst <= 0 || g.config.Period <= 0 {
+		return true
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	bucket, ok := g.buckets[source]
+	if !ok {
+		bucket = &callbackBucket{tokens: float64(g.config.Burst), updatedAt: now}
+		g.buckets[source] = bucket
+	}
+
+	refill := now.Sub(bucket.updatedAt).Seconds() / g.config.Period.Seconds() * float64(g.config.Rate)
+	bucket.tokens = math.Min(float64(g.config.Burst), bucket.tokens+refill)
+	bucket.updatedAt = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// parseSource parses a CIDR or a single IP into a network
+func parseSource(source string) (*net.IPNet, error) {
+	if strings.Contains(source, "/") {
+		_, network, err := net.ParseCIDR(source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid callback source %q: %w", source, err)
+		}
+		return network, nil
+	}
+
+	ip := net.ParseIP(source)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid callback source %q", source)
+	}
+	bits := 8 * net.IPv6len
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, bits = ip4, 8*net.IPv4len
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
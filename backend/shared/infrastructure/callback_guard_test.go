@@ -0,0 +1,158 @@
+package infrastructure
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCallbackBody = `{"order_id":"ZR-1001","status":"PAID","amount":85000,"signature":"abc"}`
+
+func newTestCallbackGuard(t *testing.T, config *CallbackGuardConfig) *CallbackGuard {
+	t.Helper()
+	guard, err := NewCallbackGuard(config)
+	if err != nil {
+		t.Fatalf("NewCallbackGuard() error = %v", err)
+	}
+	return guard
+}
+
+func newCallbackRequest(remoteAddr, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/payments/callback", strings.NewReader(body))
+	req.RemoteAddr = remoteAddr
+	return req
+}
+
+// recordingCallbackHandler records the body it received
+type recordingCallbackHandler struct {
+	calls int
+	body  string
+}
+
+func (h *recordingCallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.calls++
+	body, _ := io.ReadAll(r.Body)
+	h.body = string(body)
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestCallbackGuardAllowlistedCallback(t *testing.T) {
+	guard := newTestCallbackGuard(t, NewCallbackGuardConfig("203.0.113.0/24", "198.51.100.7"))
+
+	for _, remoteAddr := range []string{"203.0.113.25:44321", "198.51.100.7:5000"} {
+		handler := &recordingCallbackHandler{}
+		rec := httptest.NewRecorder()
+		guard.Middleware(handler).ServeHTTP(rec, newCallbackRequest(remoteAddr, testCallbackBody))
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: status = %d, want %d", remoteAddr, rec.Code, http.StatusOK)
+		}
+		if handler.calls != 1 || handler.body != testCallbackBody {
+			t.Errorf("%s: handler calls = %d body = %q, want the callback body once", remoteAddr, handler.calls, handler.body)
+		}
+	}
+}
+
+func TestCallbackGuardBlocksNonAllowlistedSource(t *testing.T) {
+	guard := newTestCallbackGuard(t, NewCallbackGuardConfig("203.0.113.0/24"))
+
+	for _, remoteAddr := range []string{"192.0.2.10:44321", "not-an-ip"} {
+		handler := &recordingCallbackHandler{}
+		rec := httptest.NewRecorder()
+		req := newCallbackRequest(remoteAddr, testCallbackBody)
+		// A spoofed forwarding header must not matter when ClientIPHeader is unset
+		req.Header.Set("X-Forwarded-For", "203.0.113.25")
+
+		guard.Middleware(handler).ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s: status = %d, want %d", remoteAddr, rec.Code, http.StatusForbidden)
+		}
+		if handler.calls != 0 {
+			t.Errorf("%s: handler called for blocked source", remoteAddr)
+		}
+	}
+}
+
+func TestCallbackGuardClientIPHeader(t *testing.T) {
+	config := NewCallbackGuardConfig("203.0.113.0/24")
+	config.ClientIPHeader = "X-Real-IP"
+	guard := newTestCallbackGuard(t, config)
+
+	req := newCallbackRequest("10.0.0.5:40000", testCallbackBody)
+	req.Header.Set("X-Real-IP", "203.0.113.25")
+	rec := httptest.NewRecorder()
+	guard.Middleware(&recordingCallbackHandler{}).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestCallbackGuardBodyLimit(t *testing.T) {
+	config := NewCallbackGuardConfig("203.0.113.0/24")
+	config.MaxBodyBytes = int64(len(testCallbackBody))
+	guard := newTestCallbackGuard(t, config)
+
+	oversized := testCallbackBody + " "
+	unknownLength := newCallbackRequest("203.0.113.25:1", oversized)
+	unknownLength.ContentLength = -1
+
+	for name, req := range map[string]*http.Request{
+		"content length": newCallbackRequest("203.0.113.25:1", oversized),
+		"unknown length": unknownLength,
+	} {
+		handler := &recordingCallbackHandler{}
+		rec := httptest.NewRecorder()
+		guard.Middleware(handler).ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("%s: status = %d, want %d", name, rec.Code, http.StatusRequestEntityTooLarge)
+		}
+		if handler.calls != 0 {
+			t.Errorf("%s: handler called for oversized body", name)
+		}
+	}
+}
+
+func TestCallbackGuardRateLimit(t *testing.T) {
+	config := NewCallbackGuardConfig("203.0.113.0/24")
+	config.Rate, config.Burst, config.Period = 1, 2, time.Second
+	guard := newTestCallbackGuard(t, config)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	guard.now = func() time.Time { return now }
+
+	serve := func(remoteAddr string) int {
+		rec := httptest.NewRecorder()
+		guard.Middleware(&recordingCallbackHandler{}).ServeHTTP(rec, newCallbackRequest(remoteAddr, testCallbackBody))
+		return rec.Code
+	}
+
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		if got := serve("203.0.113.25:1"); got != want {
+			t.Errorf("request %d: status = %d, want %d", i+1, got, want)
+		}
+	}
+
+	// Other gateway IPs have their own bucket
+	if got := serve("203.0.113.26:1"); got != http.StatusOK {
+		t.Errorf("other source: status = %d, want %d", got, http.StatusOK)
+	}
+
+	now = now.Add(time.Second)
+	if got := serve("203.0.113.25:1"); got != http.StatusOK {
+		t.Errorf("after refill: status = %d, want %d", got, http.StatusOK)
+	}
+}
+
+func TestNewCallbackGuardRejectsInvalidSource(t *testing.T) {
+	for _, source := range []string{"203.0.113.0/33", "gateway.example.com", ""} {
+		if _, err := NewCallbackGuard(NewCallbackGuardConfig(source)); err == nil {
+			t.Errorf("NewCallbackGuard(%q) error = nil, want error", source)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/southern-martin/zride/backend/shared/domain"
@@ -23,6 +24,9 @@ type DatabaseConfig struct {
 	MaxConns int
 	MaxIdle  int
 	ConnTTL  time.Duration
+
+	// SlowQueryThreshold is the latency above which repository queries are logged; 0 disables it
+	SlowQueryThreshold time.Duration
 }
 
 // NewDatabaseConfig creates database config with defaults
@@ -37,6 +41,8 @@ func NewDatabaseConfig() *DatabaseConfig {
 		MaxConns: 25,
 		MaxIdle:  5,
 		ConnTTL:  5 * time.Minute,
+
+		SlowQueryThreshold: 500 * time.Millisecond,
 	}
 }
 
@@ -102,12 +108,48 @@ func (d *Database) Health(ctx context.Context) error {
 
 // BaseRepository provides base repository implementation
 type BaseRepository struct {
-	db *Database
+	db          *Database
+	onSlowQuery func(operation string, elapsed, threshold time.Duration)
 }
 
 // NewBaseRepository creates base repository
 func NewBaseRepository(db *Database) *BaseRepository {
-	return &BaseRepository{db: db}
+	return &BaseRepository{
+		db:          db,
+		onSlowQuery: logSlowQuery,
+	}
+}
+
+// SetSlowQueryHandler replaces the default slow query logger, e.g. to export metrics
+func (r *BaseRepository) SetSlowQueryHandler(fn func(operation string, elapsed, threshold time.Duration)) {
+	r.onSlowQuery = fn
+}
+
+// ObserveQuery starts timing a query tagged with operation and returns a func that reports
+// it when it exceeded the configured threshold. Use as: defer r.ObserveQuery("users.find_by_id")()
+func (r *BaseRepository) ObserveQuery(operation string) func() {
+	if r.db == nil || r.db.config == nil || r.db.config.SlowQueryThreshold <= 0 {
+		return func() {}
+	}
+	threshold := r.db.config.SlowQueryThreshold
+
+	// Repositories built without NewBaseRepository have no handler set
+	onSlowQuery := r.onSlowQuery
+	if onSlowQuery == nil {
+		onSlowQuery = logSlowQuery
+	}
+
+	start := time.Now()
+	return func() {
+		if elapsed := time.Since(start); elapsed > threshold {
+			onSlowQuery(operation, elapsed, threshold)
+		}
+	}
+}
+
+// logSlowQuery logs a slow query warning
+func logSlowQuery(operation string, elapsed, threshold time.Duration) {
+	log.Printf("WARN slow query: operation=%s elapsed=%s threshold=%s", operation, elapsed, threshold)
 }
 
 // GetDB returns the underlying database connection
@@ -0,0 +1,56 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+)
+
+func TestObserveQueryReportsSlowQuery(t *testing.T) {
+	repo := NewBaseRepository(&Database{config: &DatabaseConfig{SlowQueryThreshold: time.Nanosecond}})
+
+	var reported []string
+	repo.SetSlowQueryHandler(func(operation string, elapsed, threshold time.Duration) {
+		if elapsed <= threshold {
+			t.Errorf("reported elapsed %s not above threshold %s", elapsed, threshold)
+		}
+		reported = append(reported, operation)
+	})
+
+	done := repo.ObserveQuery("users.find_by_id")
+	time.Sleep(time.Millisecond)
+	done()
+
+	if len(reported) != 1 || reported[0] != "users.find_by_id" {
+		t.Errorf("reported = %v, want [users.find_by_id]", reported)
+	}
+}
+
+func TestObserveQueryBelowThreshold(t *testing.T) {
+	repo := NewBaseRepository(&Database{config: &DatabaseConfig{SlowQueryThreshold: time.Hour}})
+	repo.SetSlowQueryHandler(func(operation string, elapsed, threshold time.Duration) {
+		t.Errorf("unexpected slow query report for %s", operation)
+	})
+
+	repo.ObserveQuery("users.find_by_id")()
+}
+
+func TestObserveQueryDisabled(t *testing.T) {
+	repo := NewBaseRepository(&Database{config: &DatabaseConfig{}})
+	repo.SetSlowQueryHandler(func(operation string, elapsed, threshold time.Duration) {
+		t.Errorf("unexpected slow query report for %s", operation)
+	})
+
+	done := repo.ObserveQuery("users.find_by_id")
+	time.Sleep(time.Millisecond)
+	done()
+}
+
+func TestObserveQueryWithoutConstructor(t *testing.T) {
+	// Zero-value repositories must not panic
+	(&BaseRepository{}).ObserveQuery("users.find_by_id")()
+
+	repo := &BaseRepository{db: &Database{config: &DatabaseConfig{SlowQueryThreshold: time.Nanosecond}}}
+	done := repo.ObserveQuery("users.find_by_id")
+	time.Sleep(time.Millisecond)
+	done()
+}
@@ -0,0 +1,123 @@
+// Package infrastructure provides liveness and readiness health checks
+package infrastructure
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/southern-martin/zride/backend/shared/application"
+)
+
+// Health statuses
+const (
+	HealthStatusHealthy   = "healthy"
+	HealthStatusUnhealthy = "unhealthy"
+	DependencyStatusUp    = "up"
+	DependencyStatusDown  = "down"
+)
+
+// HealthCheckFunc checks a single dependency, e.g. Database.Health or a Redis client's Ping
+type HealthCheckFunc func(ctx context.Context) error
+
+// HealthChecker serves liveness and readiness probes for a service
+type HealthChecker struct {
+	*HTTPHandler
+	service      string
+	version      string
+	startedAt    time.Time
+	timeout      time.Duration
+	dependencies map[string]HealthCheckFunc
+}
+
+// NewHealthChecker creates new health checker
+func NewHealthChecker(service, version string) *HealthChecker {
+	return &HealthChecker{
+		HTTPHandler:  NewHTTPHandler(),
+		service:      service,
+		version:      version,
+		startedAt:    time.Now(),
+		timeout:      2 * time.Second,
+		dependencies: make(map[string]HealthCheckFunc),
+	}
+}
+
+// AddDependency registers a dependency checked by the readiness probe
+func (h *HealthChecker) AddDependency(name string, check HealthCheckFunc) *HealthChecker {
+	h.dependencies[name] = check
+	return h
+}
+
+// RegisterRoutes registers /health/live and /health/ready. /health is kept as an alias of liveness
+// because the docker-compose healthchecks use it, and a dependency outage must not restart the container.
+func (h *HealthChecker) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/health/live", h.Liveness)
+	mux.HandleFunc("/health/ready", h.Readiness)
+	mux.HandleFunc("/health", h.Liveness)
+}
+
+// Liveness reports the process is up without touching dependencies
+func (h *HealthChecker) Liveness(w http.ResponseWriter, r *http.Request) {
+	h.WriteJSON(w, http.StatusOK, h.newHealthCheckDTO(HealthStatusHealthy, nil))
+}
+
+// Readiness pings every dependency and returns 503 with per-dependency status when any is down.
+// Failure details are logged rather than returned, as the endpoint is unauthenticated.
+func (h *HealthChecker) Readiness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	names := make([]string, 0, len(h.dependencies))
+	for name := range h.dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		healthy = true
+		results = make(map[string]string, len(names))
+	)
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string, check HealthCheckFunc) {
+			defer wg.Done()
+
+			status := DependencyStatusUp
+			if err := check(ctx); err != nil {
+				log.Printf("WARN readiness check failed: service=%s dependency=%s err=%v", h.service, name, err)
+				status = DependencyStatusDown
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[name] = status
+			if status != DependencyStatusUp {
+				healthy = false
+			}
+		}(name, h.dependencies[name])
+	}
+	wg.Wait()
+
+	if !healthy {
+		h.WriteJSON(w, http.StatusServiceUnavailable, h.newHealthCheckDTO(HealthStatusUnhealthy, results))
+		return
+	}
+	h.WriteJSON(w, http.StatusOK, h.newHealthCheckDTO(HealthStatusHealthy, results))
+}
+
+// newHealthCheckDTO builds the health response
+func (h *HealthChecker) newHealthCheckDTO(status string, dependencies map[string]string) application.HealthCheckDTO {
+	return application.HealthCheckDTO{
+		Status:       status,
+		Timestamp:    time.Now(),
+		Service:      h.service,
+		Version:      h.version,
+		Uptime:       time.Since(h.startedAt).Round(time.Second).String(),
+		Dependencies: dependencies,
+	}
+}
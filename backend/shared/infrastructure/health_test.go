@@ -0,0 +1,61 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/southern-martin/zride/backend/shared/application"
+)
+
+func TestHealthCheckerRoutes(t *testing.T) {
+	checker := NewHealthChecker("auth-service", "1.0.0").
+		AddDependency("postgres", func(ctx context.Context) error { return nil }).
+		AddDependency("redis", func(ctx context.Context) error {
+			return errors.New("dial tcp 10.0.3.7:6379: connect: connection refused")
+		})
+
+	mux := http.NewServeMux()
+	checker.RegisterRoutes(mux)
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantDeps   map[string]string
+	}{
+		{"/health", http.StatusOK, nil},
+		{"/health/live", http.StatusOK, nil},
+		{"/health/ready", http.StatusServiceUnavailable, map[string]string{"postgres": DependencyStatusUp, "redis": DependencyStatusDown}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if strings.Contains(rec.Body.String(), "connection refused") {
+				t.Errorf("response leaks dependency error: %s", rec.Body.String())
+			}
+
+			var body application.HealthCheckDTO
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if len(body.Dependencies) != len(tt.wantDeps) {
+				t.Fatalf("dependencies = %v, want %v", body.Dependencies, tt.wantDeps)
+			}
+			for name, status := range tt.wantDeps {
+				if body.Dependencies[name] != status {
+					t.Errorf("dependency %s = %q, want %q", name, body.Dependencies[name], status)
+				}
+			}
+		})
+	}
+}
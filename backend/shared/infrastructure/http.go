@@ -3,9 +3,11 @@ package infrastructure
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/southern-martin/zride/backend/shared/application"
 	"github.com/southern-martin/zride/backend/shared/domain"
@@ -35,10 +37,59 @@ func (h *HTTPHandler) WriteError(w http.ResponseWriter, statusCode int, err *dom
 	h.WriteJSON(w, statusCode, errorResponse)
 }
 
+// errorStatusCodes maps domain error codes to HTTP status codes; services add their own via RegisterErrorStatus
+var (
+	errorStatusMu    sync.RWMutex
+	errorStatusCodes = map[string]int{
+		domain.ErrNotFound.Code:           http.StatusNotFound,
+		domain.ErrUnauthorized.Code:       http.StatusUnauthorized,
+		domain.ErrForbidden.Code:          http.StatusForbidden,
+		domain.ErrValidation.Code:         http.StatusBadRequest,
+		domain.ErrBadRequest.Code:         http.StatusBadRequest,
+		domain.ErrConflict.Code:           http.StatusConflict,
+		domain.ErrTooManyRequests.Code:    http.StatusTooManyRequests,
+		domain.ErrPayloadTooLarge.Code:    http.StatusRequestEntityTooLarge,
+		domain.ErrServiceUnavailable.Code: http.StatusServiceUnavailable,
+	}
+)
+
+// RegisterErrorStatus maps a service-specific domain error code to an HTTP status code,
+// e.g. RegisterErrorStatus(ErrAccountLocked.Code, http.StatusForbidden)
+func RegisterErrorStatus(code string, status int) {
+	errorStatusMu.Lock()
+	defer errorStatusMu.Unlock()
+	errorStatusCodes[code] = status
+}
+
+// StatusCodeForError returns the HTTP status code for err based on its domain error code.
+// Errors that are not domain errors, or have an unknown code, map to 500.
+func StatusCodeForError(err error) int {
+	var domainErr *domain.DomainError
+	if errors.As(err, &domainErr) {
+		errorStatusMu.RLock()
+		status, ok := errorStatusCodes[domainErr.Code]
+		errorStatusMu.RUnlock()
+		if ok {
+			return status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// WriteDomainError writes err with the status code from StatusCodeForError.
+// Non-domain errors are written as ErrInternalError so internal details are not exposed.
+func (h *HTTPHandler) WriteDomainError(w http.ResponseWriter, err error) {
+	var domainErr *domain.DomainError
+	if !errors.As(err, &domainErr) {
+		domainErr = domain.ErrInternalError
+	}
+	h.WriteError(w, StatusCodeForError(domainErr), domainErr)
+}
+
 // WriteValidationError writes validation error response
 func (h *HTTPHandler) WriteValidationError(w http.ResponseWriter, message string, details map[string]interface{}) {
 	err := domain.ErrValidation.WithDetails("validation", details)
-	err.Message = message
+	err.Message = message // err is a copy, the shared ErrValidation is not modified
 	h.WriteError(w, http.StatusBadRequest, err)
 }
 
@@ -89,6 +140,22 @@ func (h *HTTPHandler) GetUserIDFromContext(r *http.Request) (string, error) {
 	return userID, nil
 }
 
+// GetUserRolesFromContext extracts user roles from request context
+func (h *HTTPHandler) GetUserRolesFromContext(r *http.Request) []string {
+	roles, _ := r.Context().Value("user_roles").([]string)
+	return roles
+}
+
+// HasRole checks if the authenticated user has the given role
+func (h *HTTPHandler) HasRole(r *http.Request, role string) bool {
+	for _, userRole := range h.GetUserRolesFromContext(r) {
+		if userRole == role {
+			return true
+		}
+	}
+	return false
+}
+
 // SetCORSHeaders sets CORS headers
 func (h *HTTPHandler) SetCORSHeaders(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -0,0 +1,62 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/southern-martin/zride/backend/shared/domain"
+)
+
+func TestStatusCodeForError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"too many requests", domain.ErrTooManyRequests, http.StatusTooManyRequests},
+		{"not found", domain.ErrNotFound, http.StatusNotFound},
+		{"conflict built per call", domain.NewDomainError("CONFLICT", "Phone already in use"), http.StatusConflict},
+		{"wrapped", fmt.Errorf("login: %w", domain.ErrTooManyRequests), http.StatusTooManyRequests},
+		{"unknown code", domain.NewDomainError("SOMETHING_ELSE", "x"), http.StatusInternalServerError},
+		{"plain error", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatusCodeForError(tt.err); got != tt.want {
+				t.Errorf("StatusCodeForError() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteDomainErrorHidesNonDomainErrors(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewHTTPHandler().WriteDomainError(rec, errors.New("pq: connection refused"))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, domain.ErrInternalError.Code) || strings.Contains(body, "connection refused") {
+		t.Errorf("unexpected body %s", body)
+	}
+}
+
+func TestRegisterErrorStatus(t *testing.T) {
+	locked := domain.NewDomainError("TEST_ACCOUNT_LOCKED", "Account is locked")
+	if got := StatusCodeForError(locked); got != http.StatusInternalServerError {
+		t.Fatalf("unregistered code status = %d, want %d", got, http.StatusInternalServerError)
+	}
+
+	RegisterErrorStatus(locked.Code, http.StatusForbidden)
+
+	rec := httptest.NewRecorder()
+	NewHTTPHandler().WriteDomainError(rec, fmt.Errorf("login: %w", locked))
+	if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), locked.Code) {
+		t.Errorf("WriteDomainError() = %d %s, want 403 with %s", rec.Code, rec.Body.String(), locked.Code)
+	}
+}
@@ -0,0 +1,158 @@
+// Package infrastructure provides Prometheus HTTP metrics middleware
+package infrastructure
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// UnmatchedRoute is the route label for 404s and paths matching no registered route
+const UnmatchedRoute = "unmatched"
+
+// HTTPMetrics records request duration and status counts labeled by route template
+type HTTPMetrics struct {
+	requestDuration *prometheus.HistogramVec
+	requestsTotal   *prometheus.CounterVec
+	routes          [][]string
+}
+
+// NewHTTPMetrics creates HTTP metrics for service and registers them with registerer.
+// routes are the service's route templates, e.g. "/trips/:id/events"; ":" segments match any value.
+// When routes are given, requests are labeled with the matching template, or UnmatchedRoute if none
+// matches. Without routes, labels fall back to NormalizeRoute. 404s are always UnmatchedRoute,
+// so scanners probing random paths cannot grow label cardinality.
+func NewHTTPMetrics(service string, registerer prometheus.Registerer, routes ...string) *HTTPMetrics {
+	m := &HTTPMetrics{
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "http_request_duration_seconds",
+			Help:        "HTTP request latency by route and method",
+			ConstLabels: prometheus.Labels{"service": service},
+			Buckets:     prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "http_requests_total",
+			Help:        "HTTP requests by route, method and status code",
+			ConstLabels: prometheus.Labels{"service": service},
+		}, []string{"route", "method", "status"}),
+	}
+
+	for _, route := range routes {
+		m.routes = append(m.routes, strings.Split(route, "/"))
+	}
+
+	registerer.MustRegister(m.requestDuration, m.requestsTotal)
+	return m
+}
+
+// Middleware records metrics for every request passing through next
+func (m *HTTPMetrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		route := m.routeLabel(r.URL.Path, recorder.status)
+		m.requestDuration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+		m.requestsTotal.WithLabelValues(route, r.Method, strconv.Itoa(recorder.status)).Inc()
+	})
+}
+
+// routeLabel returns the bounded route label for path
+func (m *HTTPMetrics) routeLabel(path string, status int) string {
+	if status == http.StatusNotFound {
+		return UnmatchedRoute
+	}
+	if len(m.routes) == 0 {
+		return NormalizeRoute(path)
+	}
+
+	segments := strings.Split(path, "/")
+	for _, route := range m.routes {
+		if matchRoute(route, segments) {
+			return strings.Join(route, "/")
+		}
+	}
+	return UnmatchedRoute
+}
+
+// matchRoute reports whether path segments match a route template's segments
+func matchRoute(route, segments []string) bool {
+	if len(route) != len(segments) {
+		return false
+	}
+	for i, segment := range route {
+		if strings.HasPrefix(segment, ":") {
+			if segments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// MetricsHandler serves the /metrics endpoint for gatherer
+func MetricsHandler(gatherer prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})
+}
+
+var (
+	uuidSegment    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	numericSegment = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// NormalizeRoute replaces ID-like path segments (UUIDs, numbers) with ":id" to keep label cardinality bounded
+func NormalizeRoute(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if uuidSegment.MatchString(segment) || numericSegment.MatchString(segment) {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// statusRecorder captures the response status code
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush forwards to the wrapped writer so streaming handlers keep working
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack forwards to the wrapped writer so websocket upgrades keep working
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
+// Unwrap exposes the wrapped writer to http.ResponseController
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,111 @@
+package infrastructure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestHTTPMetricsRouteLabels(t *testing.T) {
+	metrics := NewHTTPMetrics("trip-service", prometheus.NewRegistry(), "/trips/:id", "/trips/:id/events")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/trips/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := metrics.Middleware(mux)
+
+	for _, path := range []string{
+		"/trips/6f1c2a56-8d1e-4b6a-9a51-3f0e2c4d5b6a",
+		"/trips/TRIP-ABC",
+		"/trips/42/events",
+		"/trips/a/b/c/d",
+		"/wp-admin/setup.php",
+		"/.env",
+	} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	tests := []struct {
+		route  string
+		status string
+		want   float64
+	}{
+		{"/trips/:id", "200", 2},
+		{"/trips/:id/events", "200", 1},
+		{UnmatchedRoute, "200", 1},
+		{UnmatchedRoute, "404", 2},
+	}
+	for _, tt := range tests {
+		got := testutil.ToFloat64(metrics.requestsTotal.WithLabelValues(tt.route, http.MethodGet, tt.status))
+		if got != tt.want {
+			t.Errorf("requests{route=%q,status=%s} = %v, want %v", tt.route, tt.status, got, tt.want)
+		}
+	}
+	if got := testutil.CollectAndCount(metrics.requestsTotal); got != len(tests) {
+		t.Errorf("requests_total series = %d, want %d", got, len(tests))
+	}
+}
+
+func TestHTTPMetricsWithoutRoutesCollapsesNotFound(t *testing.T) {
+	metrics := NewHTTPMetrics("auth-service", prometheus.NewRegistry())
+	handler := metrics.Middleware(http.NotFoundHandler())
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/random/probe/1", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/another/probe", nil))
+
+	if got := testutil.ToFloat64(metrics.requestsTotal.WithLabelValues(UnmatchedRoute, http.MethodGet, "404")); got != 2 {
+		t.Errorf("unmatched 404s = %v, want 2", got)
+	}
+}
+
+func TestStatusRecorderForwardsOptionalInterfaces(t *testing.T) {
+	metrics := NewHTTPMetrics("trip-service", prometheus.NewRegistry())
+
+	t.Run("flusher", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		metrics.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			flusher, ok := w.(http.Flusher)
+			if !ok {
+				t.Fatal("wrapped writer does not implement http.Flusher")
+			}
+			w.Write([]byte("data: ping\n\n"))
+			flusher.Flush()
+		})).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+		if !rec.Flushed {
+			t.Error("flush was not forwarded")
+		}
+	})
+
+	t.Run("hijacker", func(t *testing.T) {
+		server := httptest.NewServer(metrics.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hijacker, ok := w.(http.Hijacker)
+			if !ok {
+				t.Error("wrapped writer does not implement http.Hijacker")
+				return
+			}
+			conn, buf, err := hijacker.Hijack()
+			if err != nil {
+				t.Errorf("Hijack() error = %v", err)
+				return
+			}
+			defer conn.Close()
+			buf.WriteString("HTTP/1.1 204 No Content\r\n\r\n")
+			buf.Flush()
+		})))
+		defer server.Close()
+
+		resp, err := http.Get(server.URL + "/ws")
+		if err != nil {
+			t.Fatalf("GET error = %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+		}
+	})
+}
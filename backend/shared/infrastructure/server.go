@@ -0,0 +1,121 @@
+// Package infrastructure provides HTTP server lifecycle with graceful shutdown
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// ServerConfig holds HTTP server configuration
+type ServerConfig struct {
+	Addr            string
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	ShutdownTimeout time.Duration
+}
+
+// NewServerConfig creates server config with defaults
+func NewServerConfig(addr string) *ServerConfig {
+	return &ServerConfig{
+		Addr:            addr,
+		ReadTimeout:     15 * time.Second,
+		WriteTimeout:    15 * time.Second,
+		IdleTimeout:     60 * time.Second,
+		ShutdownTimeout: 30 * time.Second,
+	}
+}
+
+// ShutdownHook releases a resource during shutdown, e.g. stopping a background worker or closing the DB pool
+type ShutdownHook func(ctx context.Context) error
+
+// RunServer serves handler until SIGINT or SIGTERM, then stops accepting connections and
+// drains in-flight requests within ShutdownTimeout. Hooks run afterwards in the given order,
+// so pass worker stops before Database.Close. Hooks also run when the server fails to start
+// or stops on its own, and get their own ShutdownTimeout independent of the drain.
+func RunServer(config *ServerConfig, handler http.Handler, hooks ...ShutdownHook) error {
+	srv := &http.Server{
+		Addr:         config.Addr,
+		Handler:      handler,
+		ReadTimeout:  config.ReadTimeout,
+		WriteTimeout: config.WriteTimeout,
+		IdleTimeout:  config.IdleTimeout,
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("server listening on %s", config.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	var runErr error
+	select {
+	case err, ok := <-serverErr:
+		if ok {
+			runErr = fmt.Errorf("server failed: %w", err)
+		}
+	case sig := <-quit:
+		log.Printf("received %s, shutting down", sig)
+		runErr = drainServer(srv, config.ShutdownTimeout)
+	}
+
+	if err := runShutdownHooks(config.ShutdownTimeout, hooks); err != nil && runErr == nil {
+		runErr = err
+	}
+
+	return runErr
+}
+
+// drainServer stops accepting connections and waits for in-flight requests up to timeout.
+// Requests still running after timeout have their connections closed, so hooks never
+// release resources those handlers are using.
+func drainServer(srv *http.Server, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		if closeErr := srv.Close(); closeErr != nil {
+			log.Printf("failed to close server connections: %v", closeErr)
+		}
+		return fmt.Errorf("failed to drain server: %w", err)
+	}
+	return nil
+}
+
+// runShutdownHooks runs every hook in order with a fresh timeout, returning the first error
+func runShutdownHooks(timeout time.Duration, hooks []ShutdownHook) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var firstErr error
+	for _, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			log.Printf("shutdown hook failed: %v", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// CloseDatabaseHook returns a shutdown hook closing the database pool
+func CloseDatabaseHook(db *Database) ShutdownHook {
+	return func(ctx context.Context) error {
+		return db.Close()
+	}
+}
@@ -0,0 +1,125 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRunServerRunsHooksWhenListenFails(t *testing.T) {
+	occupied, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer occupied.Close()
+
+	var calls []string
+	hooks := []ShutdownHook{
+		func(ctx context.Context) error { calls = append(calls, "worker"); return nil },
+		func(ctx context.Context) error { calls = append(calls, "database"); return nil },
+	}
+
+	err = RunServer(NewServerConfig(occupied.Addr().String()), http.NotFoundHandler(), hooks...)
+	if err == nil {
+		t.Fatal("RunServer() error = nil, want listen error")
+	}
+	if len(calls) != 2 || calls[0] != "worker" || calls[1] != "database" {
+		t.Errorf("hooks called = %v, want [worker database]", calls)
+	}
+}
+
+func TestRunServerHooksGetFreshContextAfterDrain(t *testing.T) {
+	addr := freeAddr(t)
+	config := NewServerConfig(addr)
+	config.ShutdownTimeout = time.Second
+
+	hookErr := errors.New("close failed")
+	var hookCtxErr error
+	hooks := []ShutdownHook{
+		func(ctx context.Context) error {
+			hookCtxErr = ctx.Err()
+			return hookErr
+		},
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- RunServer(config, http.NotFoundHandler(), hooks...) }()
+
+	waitForListener(t, addr)
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatalf("kill: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, hookErr) {
+			t.Errorf("RunServer() error = %v, want %v", err, hookErr)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunServer did not return after SIGTERM")
+	}
+	if hookCtxErr != nil {
+		t.Errorf("hook context error = %v, want live context", hookCtxErr)
+	}
+}
+
+func TestDrainServerClosesConnectionsAfterTimeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	started := make(chan struct{})
+	handlerDone := make(chan struct{})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(handlerDone)
+		close(started)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	})}
+	go srv.Serve(listener)
+
+	go http.Get("http://" + listener.Addr().String())
+	<-started
+
+	err = drainServer(srv, 50*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("drainServer() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// Closing the connection cancels the stuck request long before its own 5s fallback
+	select {
+	case <-handlerDone:
+	case <-time.After(time.Second):
+		t.Fatal("in-flight request still running after drain timed out")
+	}
+}
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func waitForListener(t *testing.T, addr string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if conn, err := net.Dial("tcp", addr); err == nil {
+			conn.Close()
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("server did not start listening on %s", addr)
+}
@@ -0,0 +1,11 @@
+-- Account lockout and suspicious login tracking (Auth Service)
+
+ALTER TABLE users
+    ADD COLUMN is_locked BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN locked_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN lock_reason TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE auth_sessions
+    ADD COLUMN is_suspicious BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_users_locked ON users(is_locked);
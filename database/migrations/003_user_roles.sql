@@ -0,0 +1,16 @@
+-- User roles encoded into access tokens (Auth Service)
+
+ALTER TABLE users
+    ADD COLUMN roles TEXT[] NOT NULL DEFAULT '{passenger}';
+
+-- Existing drivers get the driver role
+UPDATE users SET roles = array_append(roles, 'driver')
+WHERE id IN (SELECT user_id FROM driver_profiles);
+
+-- Granting roles after this migration:
+--   driver: driver-service calls POST /auth/internal/users/{id}/roles with {"role": "driver"}
+--           and the X-Service-Token header once a driver registers.
+--   admin:  granted by an existing admin via POST /auth/admin/users/{id}/roles. The first
+--           admin has to be bootstrapped by hand, e.g.:
+--           UPDATE users SET roles = array_append(roles, 'admin')
+--           WHERE id = '<user id>' AND NOT ('admin' = ANY(roles));
@@ -0,0 +1,27 @@
+-- Unique phone and email per active user (Auth Service)
+
+-- Store phones in national 0-prefixed form, matching domain.NormalizePhone
+UPDATE users SET phone = '0' || substring(phone FROM 4)
+WHERE phone ~ '^\+84[0-9]{9,10}$';
+
+UPDATE users SET phone = '0' || substring(phone FROM 3)
+WHERE phone ~ '^84[0-9]{9,10}$';
+
+-- Where active accounts share a contact, the oldest account keeps it
+UPDATE users u SET phone = ''
+FROM users keep
+WHERE u.is_active AND keep.is_active
+  AND u.phone <> '' AND u.phone = keep.phone
+  AND (keep.created_at, keep.id) < (u.created_at, u.id);
+
+UPDATE users u SET email = ''
+FROM users keep
+WHERE u.is_active AND keep.is_active
+  AND u.email <> '' AND u.email = keep.email
+  AND (keep.created_at, keep.id) < (u.created_at, u.id);
+
+CREATE UNIQUE INDEX idx_users_phone_active_unique ON users(phone)
+WHERE is_active AND phone <> '';
+
+CREATE UNIQUE INDEX idx_users_email_active_unique ON users(email)
+WHERE is_active AND email <> '';